@@ -1,13 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/joho/godotenv"
 
 	"github.com/jose-valero/popflash-queue-bot/internal/app"
 	"github.com/jose-valero/popflash-queue-bot/pkg/config"
@@ -27,11 +27,39 @@ func mustSingleInstanceLock() func() {
 	}
 }
 
+// configCheck serves `bot config check [file]`: validate and print the
+// effective configuration (secrets redacted). With a file only that file is
+// checked (no token needed); without one, the full env + CONFIG_FILE setup.
+// Exit status 1 on errors.
+func configCheck(args []string) int {
+	var cfg *config.Config
+	var err error
+	if len(args) > 0 {
+		cfg, err = config.CheckFile(args[0])
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config invalid:\n%v\n", err)
+		return 1
+	}
+	out, err := cfg.RedactedYAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config print: %v\n", err)
+		return 1
+	}
+	fmt.Print(out)
+	return 0
+}
+
 func main() {
+	if len(os.Args) >= 3 && os.Args[1] == "config" && os.Args[2] == "check" {
+		os.Exit(configCheck(os.Args[3:]))
+	}
+
 	unlock := mustSingleInstanceLock()
 	defer unlock()
 
-	_ = godotenv.Load()
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("config error: %v", err)
@@ -42,10 +70,20 @@ func main() {
 		log.Fatalf("discord session error: %v", err)
 	}
 
+	// lobby/voicewatch read VoiceStateUpdate.BeforeUpdate, which discordgo
+	// only fills while the state cache tracks voice (needs the voice intent)
+	sess.StateEnabled = true
+	sess.State.TrackVoice = true
+
+	// MessageContent and GuildMembers are privileged: enable "Message
+	// Content Intent" and "Server Members Intent" for the bot in the
+	// Discord developer portal, or the gateway refuses to connect (4014).
+	// GuildMembers lets the queued-role reconciler list the role's holders.
 	sess.Identify.Intents = discordgo.IntentsGuilds |
 		discordgo.IntentsGuildMessages |
 		discordgo.IntentsMessageContent |
-		discordgo.IntentsGuildVoiceStates
+		discordgo.IntentsGuildVoiceStates |
+		discordgo.IntentsGuildMembers
 
 	b := app.NewBot(sess, cfg)
 	b.RegisterHandlers()
@@ -60,4 +98,5 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-stop
+	b.Stop() // antes de cerrar la sesión: borra canales de voz temporales
 }
@@ -0,0 +1,13 @@
+// Package accounts - errors.go
+package accounts
+
+// aerr mirrors queue.qerr: comparable constants usable with errors.Is.
+type aerr string
+
+func (e aerr) Error() string { return string(e) }
+
+var (
+	ErrInvalid   = aerr("invalid link")
+	ErrTaken     = aerr("popflash profile already linked to another user")
+	ErrNotLinked = aerr("no linked popflash profile")
+)
@@ -0,0 +1,35 @@
+// Package accounts - helpers.go
+package accounts
+
+import (
+	"regexp"
+	"strings"
+)
+
+var reProfileID = regexp.MustCompile(`(?i)^(?:https?://(?:www\.)?popflash\.site/user/)?(\d+)/?$`)
+
+// ParseProfileID accepts a bare PopFlash user id or a profile URL
+// ("https://popflash.site/user/12345") and returns the numeric id.
+func ParseProfileID(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	m := reProfileID.FindStringSubmatch(raw)
+	if len(m) != 2 {
+		return "", false
+	}
+	return m[1], true
+}
+
+// ContainsCode reports whether any of the profile texts carries code
+// (case-insensitive, whitespace tolerant).
+func ContainsCode(code string, texts ...string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return false
+	}
+	for _, t := range texts {
+		if strings.Contains(strings.ToUpper(t), code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Package accounts - reconcile.go
+// Compares who the queue called with who actually joined a PopFlash match.
+package accounts
+
+import "github.com/jose-valero/popflash-queue-bot/internal/domain/match"
+
+// Reconciliation is the outcome of comparing Discord queue members with the
+// PopFlash users of a live match. All slices hold Discord user IDs.
+type Reconciliation struct {
+	NoShows []string // popped, linked, but absent from the match
+	Unknown []string // popped but not linked: we can't tell
+	InMatch []string // still queued, yet already playing in the match
+}
+
+// Reconcile checks popped players (called from Queue #1) and queued players
+// (still waiting) against the match roster using the stored links.
+func (s *Store) Reconcile(popped, queued []string, players []match.Player) Reconciliation {
+	inMatch := make(map[string]struct{}, len(players))
+	for _, p := range players {
+		inMatch[p.ID] = struct{}{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var r Reconciliation
+	for _, uid := range popped {
+		l, ok := s.links[uid]
+		if !ok {
+			r.Unknown = append(r.Unknown, uid)
+			continue
+		}
+		if _, ok := inMatch[l.PopflashID]; !ok {
+			r.NoShows = append(r.NoShows, uid)
+		}
+	}
+	for _, uid := range queued {
+		if l, ok := s.links[uid]; ok {
+			if _, ok := inMatch[l.PopflashID]; ok {
+				r.InMatch = append(r.InMatch, uid)
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,191 @@
+// Package accounts - store.go
+// Persistent mapping between Discord users and PopFlash profiles.
+package accounts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// Link methods, kept as plain strings so the JSON file stays readable.
+const (
+	MethodProfileCode = "profile-code"
+	MethodRecentMatch = "recent-match" // no longer offered: a public roster proves nothing
+	MethodAdmin       = "admin"
+)
+
+// PendingTTL is how long a verification code stays valid.
+const PendingTTL = 30 * time.Minute
+
+// Link associates one Discord user with one PopFlash profile.
+type Link struct {
+	DiscordID    string    `json:"discord_id"`
+	PopflashID   string    `json:"popflash_id"`
+	PopflashName string    `json:"popflash_name"`
+	Method       string    `json:"method"`
+	LinkedBy     string    `json:"linked_by"` // Discord ID of who created the link
+	LinkedAt     time.Time `json:"linked_at"`
+}
+
+// Pending is an in-flight verification: the user must place Code in their
+// PopFlash profile before calling verify.
+type Pending struct {
+	DiscordID  string
+	PopflashID string
+	Code       string
+	CreatedAt  time.Time
+}
+
+// Store keeps links in memory and mirrors them to a JSON file.
+// Pending verifications are intentionally not persisted.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	links   map[string]Link // discordID -> link
+	pending map[string]Pending
+}
+
+// Open loads the store from path. An empty path yields an in-memory store.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		links:   make(map[string]Link),
+		pending: make(map[string]Pending),
+	}
+	var saved []Link
+	if err := storage.LoadJSON(path, &saved); err != nil {
+		return s, err
+	}
+	for _, l := range saved {
+		if l.DiscordID != "" && l.PopflashID != "" {
+			s.links[l.DiscordID] = l
+		}
+	}
+	return s, nil
+}
+
+// Get returns the link of a Discord user.
+func (s *Store) Get(discordID string) (Link, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	l, ok := s.links[discordID]
+	return l, ok
+}
+
+// ByPopflashID returns the link that owns a PopFlash profile.
+func (s *Store) ByPopflashID(popflashID string) (Link, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, l := range s.links {
+		if l.PopflashID == popflashID {
+			return l, true
+		}
+	}
+	return Link{}, false
+}
+
+// Put stores (or replaces) the link for l.DiscordID. A PopFlash profile can
+// only belong to one Discord user unless force is set (admin override), in
+// which case the previous owner is unlinked.
+func (s *Store) Put(l Link, force bool) error {
+	if l.DiscordID == "" || l.PopflashID == "" {
+		return ErrInvalid
+	}
+	if l.LinkedAt.IsZero() {
+		l.LinkedAt = time.Now().UTC()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, other := range s.links {
+		if other.PopflashID == l.PopflashID && id != l.DiscordID {
+			if !force {
+				return ErrTaken
+			}
+			delete(s.links, id)
+		}
+	}
+	s.links[l.DiscordID] = l
+	delete(s.pending, l.DiscordID)
+	return s.saveLocked()
+}
+
+// Remove deletes the link of a Discord user and returns it.
+func (s *Store) Remove(discordID string) (Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.links[discordID]
+	if !ok {
+		return Link{}, ErrNotLinked
+	}
+	delete(s.links, discordID)
+	return l, s.saveLocked()
+}
+
+// All returns every link, sorted by PopFlash name.
+func (s *Store) All() []Link {
+	s.mu.RLock()
+	out := make([]Link, 0, len(s.links))
+	for _, l := range s.links {
+		out = append(out, l)
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].PopflashName) < strings.ToLower(out[j].PopflashName)
+	})
+	return out
+}
+
+// StartVerification issues a fresh code for discordID -> popflashID,
+// replacing any previous pending verification of that user.
+func (s *Store) StartVerification(discordID, popflashID string) Pending {
+	p := Pending{
+		DiscordID:  discordID,
+		PopflashID: popflashID,
+		Code:       newCode(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	s.mu.Lock()
+	s.pending[discordID] = p
+	s.mu.Unlock()
+	return p
+}
+
+// PendingFor returns the live (non-expired) verification of a user.
+func (s *Store) PendingFor(discordID string) (Pending, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pending[discordID]
+	if !ok {
+		return Pending{}, false
+	}
+	if time.Since(p.CreatedAt) > PendingTTL {
+		delete(s.pending, discordID)
+		return Pending{}, false
+	}
+	return p, true
+}
+
+func (s *Store) saveLocked() error {
+	out := make([]Link, 0, len(s.links))
+	for _, l := range s.links {
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DiscordID < out[j].DiscordID })
+	return storage.SaveJSON(s.path, out)
+}
+
+// newCode returns a short, human-typable verification code ("PFQ-1A2B3C").
+func newCode() string {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return "PFQ-" + strings.ToUpper(hex.EncodeToString(b[:]))
+}
@@ -0,0 +1,80 @@
+package accounts
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/domain/match"
+)
+
+func TestPutRejectsTakenProfile(t *testing.T) {
+	s, _ := Open("")
+	if err := s.Put(Link{DiscordID: "d1", PopflashID: "42"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(Link{DiscordID: "d2", PopflashID: "42"}, false); !errors.Is(err, ErrTaken) {
+		t.Fatalf("want ErrTaken, got %v", err)
+	}
+	// admin override moves the profile
+	if err := s.Put(Link{DiscordID: "d2", PopflashID: "42"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("d1"); ok {
+		t.Fatalf("d1 should have been unlinked")
+	}
+}
+
+func TestPersistRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "links.json")
+	s, _ := Open(path)
+	_ = s.Put(Link{DiscordID: "d1", PopflashID: "7", PopflashName: "pepe"}, false)
+
+	s2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, ok := s2.ByPopflashID("7")
+	if !ok || l.DiscordID != "d1" || l.PopflashName != "pepe" {
+		t.Fatalf("unexpected link after reload: %+v", l)
+	}
+}
+
+func TestParseProfileID(t *testing.T) {
+	cases := map[string]string{
+		"12345":                            "12345",
+		"https://popflash.site/user/987":   "987",
+		"https://popflash.site/user/987/ ": "987",
+		"http://www.PopFlash.site/user/42": "42",
+	}
+	for in, want := range cases {
+		if got, ok := ParseProfileID(in); !ok || got != want {
+			t.Fatalf("ParseProfileID(%q) = %q,%v want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"nope", "https://popflash.site/match/123", "user/55", "https://evil.example/user/7"} {
+		if got, ok := ParseProfileID(in); ok {
+			t.Fatalf("ParseProfileID(%q) = %q, expected failure", in, got)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	s, _ := Open("")
+	_ = s.Put(Link{DiscordID: "a", PopflashID: "1"}, false)
+	_ = s.Put(Link{DiscordID: "b", PopflashID: "2"}, false)
+	_ = s.Put(Link{DiscordID: "c", PopflashID: "3"}, false)
+
+	roster := []match.Player{{ID: "1"}, {ID: "3"}, {ID: "99"}}
+	r := s.Reconcile([]string{"a", "b", "x"}, []string{"c", "y"}, roster)
+
+	if len(r.NoShows) != 1 || r.NoShows[0] != "b" {
+		t.Fatalf("no-shows: %v", r.NoShows)
+	}
+	if len(r.Unknown) != 1 || r.Unknown[0] != "x" {
+		t.Fatalf("unknown: %v", r.Unknown)
+	}
+	if len(r.InMatch) != 1 || r.InMatch[0] != "c" {
+		t.Fatalf("in match: %v", r.InMatch)
+	}
+}
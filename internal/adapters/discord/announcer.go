@@ -10,9 +10,28 @@ import (
 	"github.com/jose-valero/popflash-queue-bot/internal/domain/events"
 )
 
-var announceChannelID string
+var (
+	announceMu       sync.RWMutex
+	announceChannels = map[string]string{} // guildID -> canal de anuncios de PopFlash
+)
 
-func SetAnnounceChannel(id string) { announceChannelID = id }
+// SetAnnounceChannel sets where PopFlash announces for a guild ("" = none).
+func SetAnnounceChannel(guildID, channelID string) {
+	announceMu.Lock()
+	defer announceMu.Unlock()
+	if channelID == "" {
+		delete(announceChannels, guildID)
+		return
+	}
+	announceChannels[guildID] = channelID
+}
+
+func isAnnounceChannel(guildID, channelID string) bool {
+	announceMu.RLock()
+	defer announceMu.RUnlock()
+	ch, ok := announceChannels[guildID]
+	return ok && ch == channelID
+}
 
 // Patrones
 var (
@@ -107,7 +126,7 @@ func dedupeKey(id, matchID, suffix string) string {
 }
 
 func HandleMessageCreate(_ *discordgo.Session, m *discordgo.MessageCreate) {
-	if announceChannelID == "" || m.ChannelID != announceChannelID {
+	if !isAnnounceChannel(m.GuildID, m.ChannelID) {
 		return
 	}
 
@@ -122,7 +141,7 @@ func HandleMessageCreate(_ *discordgo.Session, m *discordgo.MessageCreate) {
 }
 
 func HandleMessageUpdate(_ *discordgo.Session, ev *discordgo.MessageUpdate) {
-	if announceChannelID == "" || ev.ChannelID != announceChannelID {
+	if !isAnnounceChannel(ev.GuildID, ev.ChannelID) {
 		return
 	}
 
@@ -1,7 +1,9 @@
 package discord
 
 import (
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -54,6 +56,23 @@ func SendEphemeralComponents(
 	})
 }
 
+// SendEphemeralWithComponents responds with ephemeral text plus components
+// (e.g. an "Undo" button under an admin confirmation).
+func SendEphemeralWithComponents(s *discordgo.Session, i *discordgo.InteractionCreate, msg string, comps []discordgo.MessageComponent) error {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    msg,
+			Components: comps,
+			Flags:      ephemeralFlag,
+		},
+	})
+	if err != nil {
+		log.Printf("SendEphemeralWithComponents error: %v", err)
+	}
+	return err
+}
+
 // SendEphemeralEmbed responds with an ephemeral embed.
 func SendEphemeralEmbed(s *discordgo.Session, i *discordgo.InteractionCreate, emb *discordgo.MessageEmbed) error {
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -99,6 +118,27 @@ func UpdateEmbedWithComponents(s *discordgo.Session, i *discordgo.InteractionCre
 	return err
 }
 
+// UpdateMessageComplex replaces text, embed and components of the original
+// message (used to refresh a stale ephemeral admin panel in place).
+func UpdateMessageComplex(s *discordgo.Session, i *discordgo.InteractionCreate, content string, emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent) error {
+	embeds := []*discordgo.MessageEmbed{}
+	if emb != nil {
+		embeds = append(embeds, emb)
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Embeds:     embeds,
+			Components: comps,
+		},
+	})
+	if err != nil {
+		log.Printf("UpdateMessageComplex error: %v", err)
+	}
+	return err
+}
+
 // UpdateMessageWithComponents updates the original message with plain text.
 func UpdateMessageWithComponents(s *discordgo.Session, i *discordgo.InteractionCreate, content string, comps []discordgo.MessageComponent) error {
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -129,3 +169,81 @@ func SafeName(u *discordgo.User) string {
 	}
 	return u.Username
 }
+
+// SendDM sends a direct message to a user.
+func SendDM(s *discordgo.Session, userID, content string) error {
+	ch, err := s.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	_, err = s.ChannelMessageSend(ch.ID, content)
+	return err
+}
+
+// IsDMBlocked reports whether err means the user doesn't accept DMs from us
+// (Discord 50007: DMs closed, bot blocked or no shared server).
+func IsDMBlocked(err error) bool {
+	var re *discordgo.RESTError
+	return errors.As(err, &re) && re.Message != nil && re.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser
+}
+
+// SendMentions posts content in a channel pinging only the given users.
+func SendMentions(s *discordgo.Session, channelID string, userIDs []string, content string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, id := range userIDs {
+		b.WriteString("<@" + id + "> ")
+	}
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         b.String() + content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: userIDs},
+	})
+	return err
+}
+
+// AddRole gives a member a role (no-op on Discord's side if they have it).
+func AddRole(s *discordgo.Session, guildID, userID, roleID string) error {
+	return s.GuildMemberRoleAdd(guildID, userID, roleID)
+}
+
+// RemoveRole takes a role from a member.
+func RemoveRole(s *discordgo.Session, guildID, userID, roleID string) error {
+	return s.GuildMemberRoleRemove(guildID, userID, roleID)
+}
+
+// MembersWithRole lists the IDs of every member holding roleID, paging
+// through the member list. Needs the server members intent (requested in
+// cmd/bot/main.go, enabled in the developer portal).
+func MembersWithRole(s *discordgo.Session, guildID, roleID string) ([]string, error) {
+	var out []string
+	after := ""
+	for {
+		page, err := s.GuildMembers(guildID, after, 1000)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page {
+			if m.User == nil {
+				continue
+			}
+			for _, r := range m.Roles {
+				if r == roleID {
+					out = append(out, m.User.ID)
+					break
+				}
+			}
+		}
+		if len(page) < 1000 {
+			return out, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
+}
+
+// IsUnknownMember reports Discord's "Unknown Member" (user left the guild).
+func IsUnknownMember(err error) bool {
+	var re *discordgo.RESTError
+	return errors.As(err, &re) && re.Message != nil && re.Message.Code == discordgo.ErrCodeUnknownMember
+}
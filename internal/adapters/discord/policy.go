@@ -1,48 +1,76 @@
 // internal/adapters/discord/policy.go
-// Minimal privilege check based on ADMIN_ROLE_IDS env or Administrator permission.
+// Minimal privilege check based on the configured admin roles or Administrator permission.
 
 package discord
 
 import (
-	"os"
-	"strings"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 var (
-	adminOnce   sync.Once
-	adminRoleID = map[string]struct{}{}
+	adminMu    sync.RWMutex
+	adminBase  map[string]struct{}                // configured admin roles (ADMIN_ROLE_IDS / config file)
+	adminSetup = map[string]map[string]struct{}{} // guildID -> /setup override
 )
 
-func loadAdminRolesFromEnv() {
-	raw := os.Getenv("ADMIN_ROLE_IDS")
-	for _, id := range strings.Split(raw, ",") {
-		id = strings.TrimSpace(id)
-		if id != "" {
-			adminRoleID[id] = struct{}{}
-		}
+// ConfigureAdminRoles sets the configured admin roles (startup and config
+// reloads). A guild's /setup override still wins.
+func ConfigureAdminRoles(ids []string) {
+	adminMu.Lock()
+	defer adminMu.Unlock()
+	adminBase = roleSet(ids)
+}
+
+// SetAdminRoles replaces a guild's admin roles at runtime (/setup). An
+// empty list goes back to the configured ones.
+func SetAdminRoles(guildID string, ids []string) {
+	adminMu.Lock()
+	defer adminMu.Unlock()
+	if len(ids) == 0 {
+		delete(adminSetup, guildID)
+		return
 	}
+	adminSetup[guildID] = roleSet(ids)
 }
 
-// IsPrivileged returns true if the member has Administrator or one of ADMIN_ROLE_IDS.
+func roleSet(ids []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		m[id] = struct{}{}
+	}
+	return m
+}
+
+// IsPrivileged returns true if the member has Administrator or one of the
+// guild's admin roles.
 func IsPrivileged(i *discordgo.InteractionCreate) bool {
-	adminOnce.Do(loadAdminRolesFromEnv)
 	if i.Member == nil {
 		return false
 	}
 	if i.Member.Permissions&discordgo.PermissionAdministrator != 0 {
 		return true
 	}
+	adminMu.RLock()
+	defer adminMu.RUnlock()
+	roles, ok := adminSetup[i.GuildID]
+	if !ok {
+		roles = adminBase
+	}
 	for _, r := range i.Member.Roles {
-		if _, ok := adminRoleID[r]; ok {
+		if _, ok := roles[r]; ok {
 			return true
 		}
 	}
 	return false
 }
 
+// IsAdministrator reports the Administrator permission alone.
+func IsAdministrator(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator != 0
+}
+
 // RequirePrivileged replies ephemeral and returns false if not privileged.
 func RequirePrivileged(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
 	if IsPrivileged(i) {
@@ -0,0 +1,166 @@
+// Voice presence: who sits in which voice channel, per guild. Seeded from
+// GuildCreate payloads (which follow Ready with the full voice states; Ready
+// itself only lists unavailable stubs), kept fresh by VoiceStateUpdate and
+// re-synced from the gateway state on Resumed, so a restart or reconnect
+// doesn't make everyone already in voice look absent until they move.
+
+package discord
+
+import (
+	"log"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Presence is the voice-presence service. A seeded guild is authoritative:
+// a user missing from it is known to be out of voice.
+type Presence struct {
+	mu      sync.RWMutex
+	byGuild map[string]map[string]string // guildID -> userID -> voice channelID
+}
+
+func newPresence() *Presence {
+	return &Presence{byGuild: make(map[string]map[string]string)}
+}
+
+var presence = newPresence()
+
+// VoicePresence returns the process-wide presence service.
+func VoicePresence() *Presence { return presence }
+
+// Channel returns the user's voice channel ("" = not in voice). ok is false
+// when the guild was never seeded, i.e. we simply don't know.
+func (p *Presence) Channel(guildID, userID string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	g, ok := p.byGuild[guildID]
+	if !ok {
+		return "", false
+	}
+	return g[userID], true
+}
+
+// Members returns the user IDs connected to channelID.
+func (p *Presence) Members(guildID, channelID string) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var out []string
+	for uid, ch := range p.byGuild[guildID] {
+		if ch == channelID {
+			out = append(out, uid)
+		}
+	}
+	return out
+}
+
+// set records one voice state change; channelID "" means disconnected.
+// Unseeded guilds are left alone: a partial map would claim everyone else
+// is out of voice.
+func (p *Presence) set(guildID, userID, channelID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.byGuild[guildID]
+	if !ok {
+		return
+	}
+	if channelID == "" {
+		delete(g, userID)
+		return
+	}
+	g[userID] = channelID
+}
+
+// seed replaces a guild's snapshot with the given voice states.
+func (p *Presence) seed(guildID string, states []*discordgo.VoiceState) int {
+	g := make(map[string]string, len(states))
+	for _, vs := range states {
+		if vs != nil && vs.ChannelID != "" {
+			g[vs.UserID] = vs.ChannelID
+		}
+	}
+	p.mu.Lock()
+	p.byGuild[guildID] = g
+	p.mu.Unlock()
+	return len(g)
+}
+
+// forget drops a guild (bot removed or guild unavailable).
+func (p *Presence) forget(guildID string) {
+	p.mu.Lock()
+	delete(p.byGuild, guildID)
+	p.mu.Unlock()
+}
+
+// TrackVoiceState keeps presence fresh from live events.
+// Register it in app.Bot.RegisterHandlers().
+func TrackVoiceState(_ *discordgo.Session, ev *discordgo.VoiceStateUpdate) {
+	if ev == nil || ev.VoiceState == nil {
+		return
+	}
+	vs := ev.VoiceState
+	presence.set(vs.GuildID, vs.UserID, vs.ChannelID)
+}
+
+// TrackGuildCreate seeds presence from the guild payload, which carries the
+// voice states of everyone connected when we (re)join.
+func TrackGuildCreate(_ *discordgo.Session, ev *discordgo.GuildCreate) {
+	if ev == nil || ev.Guild == nil {
+		return
+	}
+	if ev.Unavailable {
+		presence.forget(ev.ID)
+		return
+	}
+	n := presence.seed(ev.ID, ev.VoiceStates)
+	log.Printf("[presence] guild=%s seeded %d voice state(s)", ev.ID, n)
+}
+
+// TrackGuildDelete forgets a guild we left or that went unavailable.
+func TrackGuildDelete(_ *discordgo.Session, ev *discordgo.GuildDelete) {
+	if ev != nil && ev.Guild != nil {
+		presence.forget(ev.ID)
+	}
+}
+
+// TrackResumed re-syncs after a resume; events missed while disconnected
+// are replayed into the state but may have raced our own handlers.
+func TrackResumed(s *discordgo.Session, _ *discordgo.Resumed) { ReconcileVoice(s) }
+
+// ReconcileVoice reseeds every available guild from discordgo's state.
+func ReconcileVoice(s *discordgo.Session) {
+	if s == nil || s.State == nil {
+		return
+	}
+	s.State.RLock()
+	guilds := make([]*discordgo.Guild, len(s.State.Guilds))
+	copy(guilds, s.State.Guilds)
+	s.State.RUnlock()
+
+	for _, g := range guilds {
+		if g == nil || g.Unavailable {
+			continue
+		}
+		s.State.RLock()
+		states := append([]*discordgo.VoiceState(nil), g.VoiceStates...)
+		s.State.RUnlock()
+		presence.seed(g.ID, states)
+	}
+	log.Printf("[presence] reconciled %d guild(s)", len(guilds))
+}
+
+// VoiceChannelOf returns the user's current voice channel. Presence answers
+// for seeded guilds; otherwise discordgo state is consulted.
+func VoiceChannelOf(s *discordgo.Session, guildID, userID string) (string, bool) {
+	if ch, ok := presence.Channel(guildID, userID); ok {
+		return ch, true
+	}
+	if s == nil || s.State == nil {
+		return "", false
+	}
+	vs, err := s.State.VoiceState(guildID, userID)
+	if err != nil || vs == nil {
+		return "", false
+	}
+	return vs.ChannelID, true
+}
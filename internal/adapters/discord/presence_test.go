@@ -0,0 +1,52 @@
+package discord
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPresenceSeedIsAuthoritative(t *testing.T) {
+	p := newPresence()
+	p.set("g", "u1", "v1")
+	if _, ok := p.Channel("g", "u1"); ok {
+		t.Fatal("unseeded guild should be unknown")
+	}
+
+	p.seed("g", []*discordgo.VoiceState{
+		{UserID: "u1", ChannelID: "v1"},
+		{UserID: "u2", ChannelID: "v1"},
+		{UserID: "u3", ChannelID: ""},
+	})
+	if ch, ok := p.Channel("g", "u1"); !ok || ch != "v1" {
+		t.Fatalf("u1 = %q,%v want v1", ch, ok)
+	}
+	if ch, ok := p.Channel("g", "u9"); !ok || ch != "" {
+		t.Fatalf("u9 = %q,%v want known absent", ch, ok)
+	}
+
+	p.set("g", "u2", "v2")
+	p.set("g", "u1", "")
+	got := p.Members("g", "v1")
+	if len(got) != 0 {
+		t.Fatalf("v1 members = %v, want none", got)
+	}
+	p.set("g", "u4", "v2")
+	got = p.Members("g", "v2")
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "u2" || got[1] != "u4" {
+		t.Fatalf("v2 members = %v", got)
+	}
+
+	// reseed replaces the old snapshot
+	p.seed("g", []*discordgo.VoiceState{{UserID: "u5", ChannelID: "v3"}})
+	if ch, _ := p.Channel("g", "u2"); ch != "" {
+		t.Fatalf("u2 should be gone after reseed, got %q", ch)
+	}
+
+	p.forget("g")
+	if _, ok := p.Channel("g", "u5"); ok {
+		t.Fatal("forgotten guild should be unknown")
+	}
+}
@@ -71,20 +71,28 @@ func findExistingQueueMessage(s *discordgo.Session, channelID string) (string, b
 // - Si no tenemos, intenta recuperar del historial y edita.
 // - Si no existe, crea uno nuevo y recuerda su ID.
 func PublishOrEditQueueMessage(s *discordgo.Session, channelID string, emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent) error {
+	return publishOrEdit(s, channelID, emb, comps)
+}
+
+// publishOrEdit es PublishOrEditQueueMessage con opciones de request (el
+// scheduler desactiva el retry interno de discordgo para manejar los 429).
+func publishOrEdit(s *discordgo.Session, channelID string, emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent, opts ...discordgo.RequestOption) error {
 	mu := chanLock(channelID)
 	mu.Lock()
 	defer mu.Unlock()
 
-	if _, ok := getQueueMessageID(channelID); ok {
+	if id, ok := getQueueMessageID(channelID); ok {
 		log.Printf("[publisher] EDIT (remembered) ch=%s", channelID)
-		return EditQueueMessage(s, channelID, emb, comps)
-	}
-	if id, ok := findExistingQueueMessage(s, channelID); ok {
+		if err := editMessage(s, channelID, id, emb, comps, opts...); !isUnknownMessage(err) {
+			return err
+		}
+		queueMsgIDs.Delete(channelID) // borrado a mano: lo recreamos abajo
+	} else if id, ok := findExistingQueueMessage(s, channelID); ok {
 		log.Printf("[publisher] EDIT (rehydrated id=%s) ch=%s", id, channelID)
 		SetQueueMessageID(channelID, id)
-		return EditQueueMessage(s, channelID, emb, comps)
+		return editMessage(s, channelID, id, emb, comps, opts...)
 	}
-	msg, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{emb}, Components: comps})
+	msg, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{emb}, Components: comps}, opts...)
 	if err != nil {
 		return err
 	}
@@ -100,6 +108,16 @@ func EditQueueMessage(s *discordgo.Session, channelID string, emb *discordgo.Mes
 	if !ok {
 		return nil // no recordado aún (lo resuelve PublishOrEdit)
 	}
+	err := editMessage(s, channelID, msgID, emb, comps)
+	// Si el mensaje ya no existe (10008), olvidamos el ID y dejamos que PublishOrEdit lo recree
+	if isUnknownMessage(err) {
+		queueMsgIDs.Delete(channelID)
+		return PublishOrEditQueueMessage(s, channelID, emb, comps)
+	}
+	return err
+}
+
+func editMessage(s *discordgo.Session, channelID, msgID string, emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent, opts ...discordgo.RequestOption) error {
 	embeds := []*discordgo.MessageEmbed{emb}
 	compsCopy := comps
 	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
@@ -107,13 +125,12 @@ func EditQueueMessage(s *discordgo.Session, channelID string, emb *discordgo.Mes
 		ID:         msgID,
 		Embeds:     &embeds,
 		Components: &compsCopy,
-	})
-	if err != nil {
-		// Si el mensaje ya no existe (10008), olvidamos el ID y dejamos que PublishOrEdit lo recree
-		if re, ok := err.(*discordgo.RESTError); ok && re.Message != nil && re.Message.Code == 10008 {
-			queueMsgIDs.Delete(channelID)
-			return PublishOrEditQueueMessage(s, channelID, emb, comps)
-		}
-	}
+	}, opts...)
 	return err
 }
+
+// isUnknownMessage: Discord 10008, el mensaje fue borrado.
+func isUnknownMessage(err error) bool {
+	re, ok := err.(*discordgo.RESTError)
+	return ok && re.Message != nil && re.Message.Code == 10008
+}
@@ -0,0 +1,167 @@
+package discord
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// minEditInterval spaces edits of the same channel's queue message; Discord
+// allows ~5 message edits per 5s per channel.
+const minEditInterval = time.Second
+
+// maxEditFailures bounds retries of one render after non-429 errors
+// (5xx, network); backoff doubles from editBackoff each time. A newer
+// render starts over.
+const (
+	maxEditFailures = 5
+	editBackoff     = time.Second
+)
+
+// queueRender is one rendered state of the public queue message.
+type queueRender struct {
+	emb   *discordgo.MessageEmbed
+	comps []discordgo.MessageComponent
+	hash  string
+}
+
+// editSlot is the per-channel scheduling state.
+type editSlot struct {
+	pending   *queueRender // latest state not yet sent (newer replaces older)
+	running   bool         // a worker goroutine owns this channel
+	lastHash  string       // hash of the last state Discord accepted
+	notBefore time.Time    // earliest next send (spacing, 429 retry-after or backoff)
+	failures  int          // consecutive non-429 failures of the pending render
+}
+
+// editScheduler coalesces queue message renders per channel: one worker per
+// channel sends only the latest pending state, skips states identical to the
+// last one sent and waits out rate limits before retrying.
+type editScheduler struct {
+	mu       sync.Mutex
+	slots    map[string]*editSlot
+	interval time.Duration
+	backoff  time.Duration // first retry delay after a non-429 error
+	send     func(channelID string, r *queueRender) error
+}
+
+func newEditScheduler(interval time.Duration, send func(string, *queueRender) error) *editScheduler {
+	return &editScheduler{slots: map[string]*editSlot{}, interval: interval, backoff: editBackoff, send: send}
+}
+
+var (
+	edits     *editScheduler
+	editsOnce sync.Once
+)
+
+// ScheduleQueueMessage queues a render of the channel's public queue message.
+// It returns immediately; renders scheduled while an edit is in flight or
+// rate limited are coalesced, and the last one scheduled is always the one
+// that ends up on screen.
+func ScheduleQueueMessage(s *discordgo.Session, channelID string, emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent) {
+	editsOnce.Do(func() {
+		edits = newEditScheduler(minEditInterval, func(ch string, r *queueRender) error {
+			// no dejamos que discordgo duerma en los 429: el scheduler reintenta
+			// con el estado más nuevo en vez del viejo
+			return publishOrEdit(s, ch, r.emb, r.comps, discordgo.WithRetryOnRatelimit(false))
+		})
+	})
+	edits.schedule(channelID, emb, comps)
+}
+
+func (e *editScheduler) schedule(channelID string, emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent) {
+	r := &queueRender{emb: emb, comps: comps, hash: renderHash(emb, comps)}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	slot, ok := e.slots[channelID]
+	if !ok {
+		slot = &editSlot{}
+		e.slots[channelID] = slot
+	}
+	slot.pending = r
+	slot.failures = 0
+	if !slot.running {
+		slot.running = true
+		go e.run(channelID, slot)
+	}
+}
+
+// run drains a channel's slot until nothing is pending.
+func (e *editScheduler) run(channelID string, slot *editSlot) {
+	for {
+		e.mu.Lock()
+		if wait := time.Until(slot.notBefore); wait > 0 {
+			e.mu.Unlock()
+			time.Sleep(wait)
+			continue
+		}
+		r := slot.pending
+		if r == nil {
+			slot.running = false
+			e.mu.Unlock()
+			return
+		}
+		slot.pending = nil
+		if r.hash == slot.lastHash {
+			e.mu.Unlock()
+			continue
+		}
+		e.mu.Unlock()
+
+		err := e.send(channelID, r)
+
+		e.mu.Lock()
+		var rl *discordgo.RateLimitError
+		switch {
+		case errors.As(err, &rl):
+			retry := time.Second
+			if rl.RateLimit != nil && rl.TooManyRequests != nil && rl.RetryAfter > 0 {
+				retry = rl.RetryAfter
+			}
+			log.Printf("[edits] ch=%s rate limited, retry in %s", channelID, retry)
+			slot.notBefore = time.Now().Add(retry)
+			if slot.pending == nil {
+				slot.pending = r // nothing newer: retry this one
+			}
+		case err != nil:
+			slot.failures++
+			if slot.pending != nil || slot.failures > maxEditFailures {
+				if slot.pending == nil {
+					log.Printf("[edits] ch=%s edit failed %d times, giving up: %v", channelID, slot.failures, err)
+				}
+				slot.notBefore = time.Now().Add(e.interval)
+				break
+			}
+			retry := e.backoff << (slot.failures - 1)
+			log.Printf("[edits] ch=%s edit failed: %v (retry in %s)", channelID, err, retry)
+			slot.notBefore = time.Now().Add(retry)
+			slot.pending = r // nothing newer: retry this one
+		default:
+			slot.lastHash = r.hash
+			slot.failures = 0
+			slot.notBefore = time.Now().Add(e.interval)
+		}
+		e.mu.Unlock()
+	}
+}
+
+// renderHash fingerprints what the user would see, so identical re-renders
+// (e.g. a score poll with no change) don't cost an API call.
+func renderHash(emb *discordgo.MessageEmbed, comps []discordgo.MessageComponent) string {
+	b, err := json.Marshal(struct {
+		E *discordgo.MessageEmbed      `json:"e"`
+		C []discordgo.MessageComponent `json:"c"`
+	}{emb, comps})
+	if err != nil {
+		return "" // never equal to a real hash: always send
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type fakeSender struct {
+	mu    sync.Mutex
+	sent  []string
+	fail  int // number of upcoming sends answered with a 429
+	errs  int // number of upcoming sends answered with a plain error
+	block chan struct{}
+}
+
+func (f *fakeSender) send(_ string, r *queueRender) error {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail > 0 {
+		f.fail--
+		return &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{
+			TooManyRequests: &discordgo.TooManyRequests{RetryAfter: 10 * time.Millisecond},
+		}}
+	}
+	if f.errs > 0 {
+		f.errs--
+		return errors.New("502 bad gateway")
+	}
+	f.sent = append(f.sent, r.emb.Title)
+	return nil
+}
+
+func (f *fakeSender) titles() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+func waitIdle(t *testing.T, e *editScheduler, ch string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		e.mu.Lock()
+		idle := e.slots[ch] == nil || !e.slots[ch].running
+		e.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	t.Fatalf("scheduler did not drain")
+}
+
+func emb(title string) *discordgo.MessageEmbed { return &discordgo.MessageEmbed{Title: title} }
+
+func TestScheduleCoalescesAndLastWins(t *testing.T) {
+	f := &fakeSender{block: make(chan struct{})}
+	e := newEditScheduler(0, f.send)
+
+	e.schedule("c", emb("1"), nil) // in flight, blocked
+	time.Sleep(5 * time.Millisecond)
+	e.schedule("c", emb("2"), nil)
+	e.schedule("c", emb("3"), nil)
+	close(f.block)
+	waitIdle(t, e, "c")
+
+	got := f.titles()
+	if len(got) != 2 || got[0] != "1" || got[1] != "3" {
+		t.Fatalf("want [1 3], got %v", got)
+	}
+}
+
+func TestScheduleSkipsUnchanged(t *testing.T) {
+	f := &fakeSender{}
+	e := newEditScheduler(0, f.send)
+
+	e.schedule("c", emb("same"), nil)
+	waitIdle(t, e, "c")
+	e.schedule("c", emb("same"), nil)
+	waitIdle(t, e, "c")
+
+	if got := f.titles(); len(got) != 1 {
+		t.Fatalf("unchanged render must be skipped, sent %v", got)
+	}
+}
+
+func TestScheduleRetriesAfterRateLimit(t *testing.T) {
+	f := &fakeSender{fail: 2}
+	e := newEditScheduler(0, f.send)
+
+	e.schedule("c", emb("x"), nil)
+	waitIdle(t, e, "c")
+
+	if got := f.titles(); len(got) != 1 || got[0] != "x" {
+		t.Fatalf("want [x] after retries, got %v", got)
+	}
+}
+
+func TestScheduleRetriesAfterServerError(t *testing.T) {
+	f := &fakeSender{errs: 2}
+	e := newEditScheduler(0, f.send)
+	e.backoff = time.Millisecond
+
+	e.schedule("c", emb("x"), nil)
+	waitIdle(t, e, "c")
+
+	if got := f.titles(); len(got) != 1 || got[0] != "x" {
+		t.Fatalf("want [x] after retries, got %v", got)
+	}
+
+	f.mu.Lock()
+	f.errs = maxEditFailures + 1
+	f.mu.Unlock()
+	e.schedule("c", emb("y"), nil)
+	waitIdle(t, e, "c")
+	if got := f.titles(); len(got) != 1 {
+		t.Fatalf("a render failing every time should be dropped, got %v", got)
+	}
+}
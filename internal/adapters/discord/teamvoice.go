@@ -0,0 +1,102 @@
+// Temporary per-team voice channels for a popped match: pick a category the
+// voice policy allows, create one channel per team, move players, clean up.
+
+package discord
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// TeamVoiceEnabled reports whether the guild creates team voice channels.
+func TeamVoiceEnabled(guildID string) bool {
+	return policyFor(guildID).guild.TeamVoice
+}
+
+// TeamVoiceCategory picks where team channels go: the guild's team voice
+// category if the voice policy allows it, else the first allowed category of
+// the guild (with no allow-lists configured, the team category is required).
+// Channels created there count as "allowed voice" for the queue checks.
+func TeamVoiceCategory(s *discordgo.Session, guildID string) (string, bool) {
+	p := policyFor(guildID)
+	if cat := p.guild.TeamVoiceCategoryID; cat != "" {
+		return cat, p.noAllowLists() || p.categoryAllowed(s, cat)
+	}
+
+	var chans []*discordgo.Channel
+	if g, err := s.State.Guild(guildID); err == nil && g != nil {
+		chans = g.Channels
+	}
+	if len(chans) == 0 {
+		chans, _ = s.GuildChannels(guildID)
+	}
+	cats := make([]*discordgo.Channel, 0)
+	for _, ch := range chans {
+		if ch != nil && ch.Type == discordgo.ChannelTypeGuildCategory && p.categoryAllowed(s, ch.ID) {
+			cats = append(cats, ch)
+		}
+	}
+	if len(cats) == 0 {
+		return "", false
+	}
+	sort.Slice(cats, func(i, j int) bool { return cats[i].Position < cats[j].Position })
+	return cats[0].ID, true
+}
+
+// CreateTeamChannels creates one voice channel per team under categoryID.
+// On error, channels created so far are returned so the caller can delete them.
+func CreateTeamChannels(s *discordgo.Session, guildID, categoryID, label string, teams, userLimit int) ([]string, error) {
+	ids := make([]string, 0, teams)
+	for t := 1; t <= teams; t++ {
+		ch, err := s.GuildChannelCreateComplex(guildID, discordgo.GuildChannelCreateData{
+			Name:      fmt.Sprintf("Team %d · %s", t, label),
+			Type:      discordgo.ChannelTypeGuildVoice,
+			ParentID:  categoryID,
+			UserLimit: userLimit,
+		})
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, ch.ID)
+	}
+	return ids, nil
+}
+
+// MoveToVoice moves users into channelID. Users not connected to voice can't
+// be moved by Discord, so they are skipped; it returns how many were moved.
+func MoveToVoice(s *discordgo.Session, guildID, channelID string, userIDs []string) (int, error) {
+	moved := 0
+	var firstErr error
+	for _, uid := range userIDs {
+		if cur, ok := VoiceChannelOf(s, guildID, uid); !ok || cur == "" || cur == channelID {
+			continue
+		}
+		if err := s.GuildMemberMove(guildID, uid, &channelID); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		moved++
+	}
+	return moved, firstErr
+}
+
+// DeleteChannels removes channels, ignoring ones already gone.
+func DeleteChannels(s *discordgo.Session, ids []string) error {
+	var firstErr error
+	for _, id := range ids {
+		if _, err := s.ChannelDelete(id); err != nil && !isUnknownChannel(err) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// isUnknownChannel: Discord 10003, the channel no longer exists.
+func isUnknownChannel(err error) bool {
+	re, ok := err.(*discordgo.RESTError)
+	return ok && re.Message != nil && re.Message.Code == discordgo.ErrCodeUnknownChannel
+}
@@ -1,111 +1,161 @@
-// Voice policy helpers: read env, decide if a channel/user is allowed,
-// cache last voice channel, and expose a tiny handler to keep that cache fresh.
+// Voice policy helpers: hold the configured policy and decide if a
+// channel/user is allowed.
+// Who is in which channel lives in presence.go.
 
 package discord
 
 import (
-	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-var (
-	voiceOnce sync.Once
+// VoicePolicy is the voice configuration, set from pkg/config with
+// ConfigureVoice. The zero value allows every channel and requires nothing.
+type VoicePolicy struct {
+	RequireToJoin   bool                     // enforce voice on join and while queued
+	CategoryIDs     []string                 // allow-list by category ID
+	CategoryNames   []string                 // allow-list by category NAME (case-insensitive)
+	ChannelPrefixes []string                 // allow-list by channel NAME prefix
+	AFKChannelID    string                   // overrides the guild AFK channel
+	Grace           time.Duration            // out of voice before removal from the queue
+	GraceByChannel  map[string]time.Duration // Grace per queue channel
+	LobbyLeaveDelay time.Duration            // out of the lobby before removal
+}
+
+// GuildVoice is the part of the voice policy each guild has its own of,
+// resolved by the app (config entry, then /setup). Empty CategoryIDs and a
+// zero Grace keep the configured ones.
+type GuildVoice struct {
+	CategoryIDs         []string      // allow-list by category ID
+	Grace               time.Duration // out of voice before removal from the queue
+	LobbyChannelID      string        // "queue lobby": entering it joins the queue
+	TeamVoice           bool          // temporary team channels (see teamvoice.go)
+	TeamVoiceCategoryID string
+}
 
-	voiceRequireToJoin     bool
-	allowedCategoryIDs     map[string]struct{}
-	allowedCategoryNames   map[string]struct{}
-	allowedChannelPrefixes []string
-	afkChannelOverride     string
+// voicePolicy is a VoicePolicy, with a guild's GuildVoice laid over it,
+// ready for lookups. Never mutated once built.
+type voicePolicy struct {
+	VoicePolicy
+	guild         GuildVoice
+	categoryIDs   map[string]struct{}
+	categoryNames map[string]struct{}
+	prefixes      []string
+}
 
-	// (guildID:userID) -> last voice channelID
-	lastVoice sync.Map
+var (
+	voiceMu    sync.RWMutex
+	voiceBase  = VoicePolicy{Grace: 120 * time.Second, LobbyLeaveDelay: 60 * time.Second}
+	guildVoice = map[string]GuildVoice{} // guildID -> its part of the policy
+	voicePol   = buildVoicePolicy(voiceBase, nil)
+	guildPol   = map[string]*voicePolicy{} // guilds with a GuildVoice
 )
 
-func loadVoicePolicyFromEnv() {
-	// VOICE_REQUIRE_TO_JOIN = "true"/"1" enables the check in your join handlers
-	v := strings.TrimSpace(os.Getenv("VOICE_REQUIRE_TO_JOIN"))
-	voiceRequireToJoin = v == "1" || strings.EqualFold(v, "true")
-
-	// Allow-list by category IDs (comma separated)
-	allowedCategoryIDs = make(map[string]struct{})
-	for _, id := range strings.Split(os.Getenv("VOICE_ALLOWED_CATEGORY_IDS"), ",") {
-		id = strings.Trim(strings.TrimSpace(id), `"'`)
-		if id != "" {
-			allowedCategoryIDs[id] = struct{}{}
+func buildVoicePolicy(p VoicePolicy, gv *GuildVoice) *voicePolicy {
+	var guild GuildVoice
+	if gv != nil {
+		guild = *gv
+		if len(gv.CategoryIDs) > 0 {
+			p.CategoryIDs = gv.CategoryIDs
 		}
-	}
-
-	// Allow-list by category NAMES (comma separated; case-insensitive)
-	allowedCategoryNames = make(map[string]struct{})
-	for _, n := range strings.Split(os.Getenv("VOICE_ALLOWED_CATEGORY_NAMES"), ",") {
-		n = strings.ToLower(strings.Trim(strings.TrimSpace(n), `"'`))
-		if n != "" {
-			allowedCategoryNames[n] = struct{}{}
+		if gv.Grace > 0 {
+			p.Grace = gv.Grace
 		}
 	}
-
-	// Optional allow-list by channel NAME prefix (comma separated)
-	for _, p := range strings.Split(os.Getenv("VOICE_ALLOWED_CHANNEL_PREFIXES"), ",") {
-		p = strings.ToLower(strings.TrimSpace(p))
-		if p != "" {
-			allowedChannelPrefixes = append(allowedChannelPrefixes, p)
-		}
+	vp := &voicePolicy{
+		VoicePolicy:   p,
+		guild:         guild,
+		categoryIDs:   make(map[string]struct{}),
+		categoryNames: make(map[string]struct{}),
+	}
+	for _, id := range p.CategoryIDs {
+		vp.categoryIDs[id] = struct{}{}
+	}
+	for _, n := range p.CategoryNames {
+		vp.categoryNames[strings.ToLower(strings.TrimSpace(n))] = struct{}{}
 	}
+	for _, pre := range p.ChannelPrefixes {
+		vp.prefixes = append(vp.prefixes, strings.ToLower(strings.TrimSpace(pre)))
+	}
+	return vp
+}
 
-	// Optional explicit AFK channel override
-	afkChannelOverride = strings.TrimSpace(os.Getenv("AFK_CHANNEL_ID"))
+func policy() *voicePolicy {
+	voiceMu.RLock()
+	defer voiceMu.RUnlock()
+	return voicePol
 }
 
-// VoiceRequireToJoin reports whether /join should enforce voice policy.
-func VoiceRequireToJoin() bool {
-	voiceOnce.Do(loadVoicePolicyFromEnv)
-	return voiceRequireToJoin
+// policyFor is the policy of one guild: the configured one with its
+// GuildVoice swapped in.
+func policyFor(guildID string) *voicePolicy {
+	voiceMu.RLock()
+	defer voiceMu.RUnlock()
+	if p, ok := guildPol[guildID]; ok {
+		return p
+	}
+	return voicePol
 }
 
-// TrackVoiceState is a lightweight handler to keep the "last voice channel"
-// cache updated. Register it in app.Bot.RegisterHandlers().
-func TrackVoiceState(_ *discordgo.Session, ev *discordgo.VoiceStateUpdate) {
-	voiceOnce.Do(loadVoicePolicyFromEnv)
-	if ev == nil || ev.VoiceState == nil {
-		return
+// ConfigureVoice replaces the voice policy (startup and config reloads).
+func ConfigureVoice(p VoicePolicy) {
+	voiceMu.Lock()
+	defer voiceMu.Unlock()
+	voiceBase = p
+	voicePol = buildVoicePolicy(voiceBase, nil)
+	for g, gv := range guildVoice {
+		guildPol[g] = buildVoicePolicy(voiceBase, &gv)
 	}
-	vs := ev.VoiceState
-	lastVoice.Store(vs.GuildID+":"+vs.UserID, vs.ChannelID)
 }
 
-func getLastVoice(guildID, userID string) (string, bool) {
-	k := guildID + ":" + userID
-	v, ok := lastVoice.Load(k)
-	if !ok {
-		return "", false
+// SetGuildVoice replaces a guild's part of the voice policy (start, config
+// reloads and /setup). The zero GuildVoice goes back to the configured
+// policy.
+func SetGuildVoice(guildID string, gv GuildVoice) {
+	voiceMu.Lock()
+	defer voiceMu.Unlock()
+	if len(gv.CategoryIDs) == 0 && gv.Grace == 0 && gv.LobbyChannelID == "" && !gv.TeamVoice && gv.TeamVoiceCategoryID == "" {
+		delete(guildVoice, guildID)
+		delete(guildPol, guildID)
+		return
 	}
-	return v.(string), true
+	guildVoice[guildID] = gv
+	guildPol[guildID] = buildVoicePolicy(voiceBase, &gv)
 }
 
-// IsUserInAllowedVoice returns true if the user is currently in an allowed voice channel.
-func IsUserInAllowedVoice(s *discordgo.Session, guildID, userID string) bool {
-	voiceOnce.Do(loadVoicePolicyFromEnv)
+// LobbyChannelID is the guild's queue lobby voice channel ("" = lobby mode
+// off).
+func LobbyChannelID(guildID string) string { return policyFor(guildID).guild.LobbyChannelID }
 
-	// Prefer our cache (fast, works across events)
-	if chID, ok := getLastVoice(guildID, userID); ok && chID != "" {
-		return ChannelAllowedByCategory(s, chID)
-	}
+// LobbyLeaveDelay is how long a player may be out of the lobby before
+// being removed from the queue.
+func LobbyLeaveDelay() time.Duration { return policy().LobbyLeaveDelay }
 
-	// Fallback to discordgo state
-	vs, err := s.State.VoiceState(guildID, userID)
-	if err != nil || vs == nil || vs.ChannelID == "" {
-		return false
+// VoiceGrace is how long a queued player of queueChannelID (in guildID) may
+// stay out of allowed voice (or in AFK) before being removed from the queue.
+func VoiceGrace(guildID, queueChannelID string) time.Duration {
+	p := policyFor(guildID)
+	if g, ok := p.GraceByChannel[queueChannelID]; ok {
+		return g
 	}
-	return ChannelAllowedByCategory(s, vs.ChannelID)
+	return p.Grace
+}
+
+// VoiceRequireToJoin reports whether /join should enforce voice policy.
+func VoiceRequireToJoin() bool { return policy().RequireToJoin }
+
+// IsUserInAllowedVoice returns true if the user is currently in an allowed voice channel.
+func IsUserInAllowedVoice(s *discordgo.Session, guildID, userID string) bool {
+	chID, _ := VoiceChannelOf(s, guildID, userID)
+	return chID != "" && ChannelAllowedByCategory(s, chID)
 }
 
 // ChannelAllowedByCategory applies the configured allow-lists.
 // If no allow-lists are configured, ALL channels are considered allowed.
 func ChannelAllowedByCategory(s *discordgo.Session, channelID string) bool {
-	voiceOnce.Do(loadVoicePolicyFromEnv)
 	if channelID == "" {
 		return false
 	}
@@ -119,48 +169,56 @@ func ChannelAllowedByCategory(s *discordgo.Session, channelID string) bool {
 	}
 
 	// 1) Allow by channel name prefix (if configured)
-	if len(allowedChannelPrefixes) > 0 {
+	p := policyFor(ch.GuildID)
+	if len(p.prefixes) > 0 {
 		name := strings.ToLower(ch.Name)
-		for _, pref := range allowedChannelPrefixes {
+		for _, pref := range p.prefixes {
 			if strings.HasPrefix(name, pref) {
 				return true
 			}
 		}
 	}
 
-	// 2) Allow by category ID
-	if ch.ParentID != "" {
-		if _, ok := allowedCategoryIDs[ch.ParentID]; ok {
-			return true
-		}
-
-		// 3) Allow by category NAME (case-insensitive)
-		if len(allowedCategoryNames) > 0 {
-			var catName string
-			if cat, _ := s.State.Channel(ch.ParentID); cat != nil {
-				catName = cat.Name
-			} else if cat2, _ := s.Channel(ch.ParentID); cat2 != nil {
-				catName = cat2.Name
-			}
-			if _, ok := allowedCategoryNames[strings.ToLower(strings.TrimSpace(catName))]; ok {
-				return true
-			}
-		}
+	// 2) + 3) Allow by category ID or NAME
+	if ch.ParentID != "" && p.categoryAllowed(s, ch.ParentID) {
+		return true
 	}
 
 	// 4) If no allow-lists configured, default to ALLOW
-	return len(allowedCategoryIDs) == 0 && len(allowedCategoryNames) == 0 && len(allowedChannelPrefixes) == 0
+	return p.noAllowLists()
+}
+
+// noAllowLists: no category ID/NAME or channel prefix allow-list is set.
+func (p *voicePolicy) noAllowLists() bool {
+	return len(p.categoryIDs) == 0 && len(p.categoryNames) == 0 && len(p.prefixes) == 0
+}
+
+// categoryAllowed checks a category against the ID and NAME allow-lists.
+func (p *voicePolicy) categoryAllowed(s *discordgo.Session, categoryID string) bool {
+	if _, ok := p.categoryIDs[categoryID]; ok {
+		return true
+	}
+	if len(p.categoryNames) == 0 {
+		return false
+	}
+	var catName string
+	if cat, _ := s.State.Channel(categoryID); cat != nil {
+		catName = cat.Name
+	} else if cat2, _ := s.Channel(categoryID); cat2 != nil {
+		catName = cat2.Name
+	}
+	_, ok := p.categoryNames[strings.ToLower(strings.TrimSpace(catName))]
+	return ok
 }
 
 // IsAFKChannel reports whether channelID is the guild AFK channel.
-// Honors AFK_CHANNEL_ID override if set.
+// Honors the AFKChannelID override if set.
 func IsAFKChannel(s *discordgo.Session, guildID, channelID string) bool {
-	voiceOnce.Do(loadVoicePolicyFromEnv)
 	if channelID == "" {
 		return false
 	}
-	if afkChannelOverride != "" {
-		return channelID == afkChannelOverride
+	if afk := policy().AFKChannelID; afk != "" {
+		return channelID == afk
 	}
 	g, _ := s.State.Guild(guildID)
 	if g == nil {
@@ -168,3 +226,23 @@ func IsAFKChannel(s *discordgo.Session, guildID, channelID string) bool {
 	}
 	return g != nil && g.AfkChannelID != "" && g.AfkChannelID == channelID
 }
+
+// VoiceMembers lists the (non-bot) users connected to a voice channel, per
+// presence. Members missing from the member cache are fetched.
+func VoiceMembers(s *discordgo.Session, guildID, channelID string) []*discordgo.User {
+	var out []*discordgo.User
+	for _, uid := range presence.Members(guildID, channelID) {
+		m, _ := s.State.Member(guildID, uid)
+		if m == nil || m.User == nil {
+			m, _ = s.GuildMember(guildID, uid)
+		}
+		if m == nil || m.User == nil {
+			out = append(out, &discordgo.User{ID: uid, Username: uid})
+			continue
+		}
+		if !m.User.Bot {
+			out = append(out, m.User)
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package discord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVoiceCategoriesPerGuild(t *testing.T) {
+	saved := voiceBase
+	t.Cleanup(func() {
+		SetGuildVoice("g1", GuildVoice{})
+		ConfigureVoice(saved)
+	})
+	has := func(guildID, cat string) bool {
+		_, ok := policyFor(guildID).categoryIDs[cat]
+		return ok
+	}
+
+	ConfigureVoice(VoicePolicy{CategoryIDs: []string{"base"}})
+	SetGuildVoice("g1", GuildVoice{CategoryIDs: []string{"c1"}})
+	if !has("g1", "c1") || has("g1", "base") {
+		t.Fatal("g1 should use its /setup categories only")
+	}
+	if !has("g2", "base") || has("g2", "c1") {
+		t.Fatal("g2 should keep the configured categories")
+	}
+
+	// a config reload keeps the override
+	ConfigureVoice(VoicePolicy{CategoryIDs: []string{"base2"}})
+	if !has("g1", "c1") || !has("g2", "base2") {
+		t.Fatal("reload lost the per-guild override")
+	}
+
+	SetGuildVoice("g1", GuildVoice{})
+	if !has("g1", "base2") {
+		t.Fatal("clearing the override should fall back to the config")
+	}
+}
+
+func TestGuildVoiceSettings(t *testing.T) {
+	saved := voiceBase
+	t.Cleanup(func() {
+		SetGuildVoice("g1", GuildVoice{})
+		ConfigureVoice(saved)
+	})
+
+	ConfigureVoice(VoicePolicy{
+		Grace:          2 * time.Minute,
+		GraceByChannel: map[string]time.Duration{"q2": 5 * time.Minute},
+	})
+	SetGuildVoice("g1", GuildVoice{Grace: 30 * time.Second, LobbyChannelID: "lobby1", TeamVoice: true})
+
+	if got := VoiceGrace("g1", "q1"); got != 30*time.Second {
+		t.Fatalf("g1 grace = %v, want 30s", got)
+	}
+	if got := VoiceGrace("g1", "q2"); got != 5*time.Minute {
+		t.Fatalf("per channel grace should win, got %v", got)
+	}
+	if got := VoiceGrace("g2", "q1"); got != 2*time.Minute {
+		t.Fatalf("g2 grace = %v, want the configured 2m", got)
+	}
+	if LobbyChannelID("g1") != "lobby1" || LobbyChannelID("g2") != "" {
+		t.Fatal("the lobby should be g1's only")
+	}
+	if !TeamVoiceEnabled("g1") || TeamVoiceEnabled("g2") {
+		t.Fatal("team voice should be on in g1 only")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/jose-valero/popflash-queue-bot/internal/domain/match"
 	"github.com/jose-valero/popflash-queue-bot/internal/ui"
 )
 
@@ -25,9 +26,30 @@ func New(base, key string) *Client {
 
 // Mantiene la firma actual que usa el resto del código.
 func (c *Client) MatchCard(ctx context.Context, id string) (ui.MatchCard, error) {
-	var zero ui.MatchCard
+	card, _, err := c.MatchDetails(ctx, id)
+	return card, err
+}
+
+// MatchDetails returns the UI card plus the PopFlash users of the match,
+// with one request.
+func (c *Client) MatchDetails(ctx context.Context, id string) (ui.MatchCard, []match.Player, error) {
+	var payload getMatchResp
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/api/rest/match/%s", c.Base, id), &payload); err != nil {
+		return ui.MatchCard{}, nil, err
+	}
+	return toUIMatchCard(payload.Match), toPlayers(payload.Match), nil
+}
+
+// User fetches a public PopFlash profile by user id.
+func (c *Client) User(ctx context.Context, id string) (match.Profile, error) {
+	var payload getUserResp
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/api/rest/user/%s", c.Base, id), &payload); err != nil {
+		return match.Profile{}, err
+	}
+	return toProfile(payload.User), nil
+}
 
-	url := fmt.Sprintf("%s/api/rest/match/%s", c.Base, id)
+func (c *Client) getJSON(ctx context.Context, url string, out any) error {
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "popflash-queue-bot/1.0")
@@ -37,18 +59,12 @@ func (c *Client) MatchCard(ctx context.Context, id string) (ui.MatchCard, error)
 
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
-		return zero, fmt.Errorf("popflash GET %s: %w", url, err)
+		return fmt.Errorf("popflash GET %s: %w", url, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return zero, fmt.Errorf("popflash GET %s -> %d", url, resp.StatusCode)
-	}
-
-	var payload getMatchResp
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return zero, err
+		return fmt.Errorf("popflash GET %s -> %d", url, resp.StatusCode)
 	}
-
-	return toUIMatchCard(payload.Match), nil
+	return json.NewDecoder(resp.Body).Decode(out)
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jose-valero/popflash-queue-bot/internal/domain/match"
 	"github.com/jose-valero/popflash-queue-bot/internal/ui"
 )
 
@@ -39,6 +40,33 @@ func splitTeams(m apiMatch) (t1, t2 []string) {
 	return
 }
 
+// toPlayers keeps the PopFlash ids so callers can match linked accounts.
+func toPlayers(m apiMatch) []match.Player {
+	out := make([]match.Player, 0, len(m.Users))
+	for _, um := range m.Users {
+		if um.User == nil || um.User.ID == nil {
+			continue
+		}
+		team := 1
+		if um.Team != nil && *um.Team == 2 {
+			team = 2
+		}
+		out = append(out, match.Player{ID: itoa(*um.User.ID), Name: safeStr(um.User.Name), Team: team})
+	}
+	return out
+}
+
+func toProfile(u apiUser) match.Profile {
+	p := match.Profile{Name: safeStr(u.Name)}
+	if u.ID != nil {
+		p.ID = itoa(*u.ID)
+	}
+	if u.Bio != nil {
+		p.Bio = *u.Bio
+	}
+	return p
+}
+
 func itoa(i int) string { return fmt.Sprintf("%d", i) }
 
 func safeStr(p *string) string {
@@ -5,8 +5,14 @@ type getMatchResp struct {
 	Match apiMatch `json:"match"`
 }
 
+type getUserResp struct {
+	User apiUser `json:"user"`
+}
+
 type apiUser struct {
+	ID   *int    `json:"id"`
 	Name *string `json:"name"`
+	Bio  *string `json:"bio"`
 }
 
 type apiUsersMatch struct {
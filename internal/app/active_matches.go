@@ -7,43 +7,75 @@ import (
 	"github.com/jose-valero/popflash-queue-bot/internal/ui"
 )
 
+// Active matches, per guild: each guild's queue message only shows its own.
 var (
-	activeMu   sync.RWMutex
-	activeByID = map[string]ui.MatchCard{} // id -> card
+	activeMu      sync.RWMutex
+	activeByGuild = map[string]map[string]ui.MatchCard{} // guildID -> id -> card
 )
 
-func ActivePut(card ui.MatchCard) {
+func ActivePut(guildID string, card ui.MatchCard) {
 	activeMu.Lock()
-	activeByID[card.ID] = card
+	m := activeByGuild[guildID]
+	if m == nil {
+		m = map[string]ui.MatchCard{}
+		activeByGuild[guildID] = m
+	}
+	m[card.ID] = card
 	activeMu.Unlock()
+	invalidate(queueChannelOf(guildID))
 }
 
-func ActiveUpdateScore(id string, s1, s2 *int) {
+func ActiveUpdateScore(guildID, id string, s1, s2 *int) {
 	activeMu.Lock()
-	if c, ok := activeByID[id]; ok {
+	if c, ok := activeByGuild[guildID][id]; ok {
 		c.Score1, c.Score2 = s1, s2
-		activeByID[id] = c
+		activeByGuild[guildID][id] = c
 	}
 	activeMu.Unlock()
+	invalidate(queueChannelOf(guildID))
 }
 
-func ActiveRemove(id string) {
+func ActiveRemove(guildID, id string) {
 	activeMu.Lock()
-	delete(activeByID, id)
+	delete(activeByGuild[guildID], id)
+	if len(activeByGuild[guildID]) == 0 {
+		delete(activeByGuild, guildID)
+	}
 	activeMu.Unlock()
+	invalidate(queueChannelOf(guildID))
+}
+
+// ActiveGet returns the guild's active match card with that id.
+func ActiveGet(guildID, id string) (ui.MatchCard, bool) {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	c, ok := activeByGuild[guildID][id]
+	return c, ok
 }
 
-func ActiveCount() int {
+func ActiveCount(guildID string) int {
 	activeMu.RLock()
-	n := len(activeByID)
+	n := len(activeByGuild[guildID])
 	activeMu.RUnlock()
 	return n
 }
 
-func ActiveList() []ui.MatchCard {
+// ActiveGuilds lists the guilds with at least one active match.
+func ActiveGuilds() []string {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	out := make([]string, 0, len(activeByGuild))
+	for g := range activeByGuild {
+		out = append(out, g)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func ActiveList(guildID string) []ui.MatchCard {
 	activeMu.RLock()
-	out := make([]ui.MatchCard, 0, len(activeByID))
-	for _, c := range activeByID {
+	out := make([]ui.MatchCard, 0, len(activeByGuild[guildID]))
+	for _, c := range activeByGuild[guildID] {
 		out = append(out, c)
 	}
 	activeMu.RUnlock()
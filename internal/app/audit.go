@@ -0,0 +1,98 @@
+// internal/app/audit.go
+package app
+
+import (
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// audited runs mutate on the channel's executor, snapshots the queues around
+// it and records the action only if mutate succeeds. e carries
+// Action/Target/Detail; actor, channel and snapshots are filled here.
+func audited(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string, e audit.Entry, mutate func() error) error {
+	var before, after []*queue.Queue
+	if err := serialize(channelID, func() error {
+		before, _ = qman.Queues(channelID)
+		if err := mutate(); err != nil {
+			return err
+		}
+		after, _ = qman.Queues(channelID)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	e.ChannelID = channelID
+	e.Before, e.After = audit.SnapshotOf(before), audit.SnapshotOf(after)
+	recordAudit(s, i, e)
+	return nil
+}
+
+// recordAudit stores e (actor and guild taken from the interaction) and
+// mirrors it to the guild's audit channel when configured.
+func recordAudit(s *discordgo.Session, i *discordgo.InteractionCreate, e audit.Entry) {
+	if u := d.UserOf(i); u != nil {
+		e.ActorID, e.ActorName = u.ID, u.Username
+	}
+	e.GuildID = i.GuildID
+	if e.ChannelID == "" {
+		e.ChannelID = i.ChannelID
+	}
+	e, err := auditLog.Record(e)
+	if err != nil {
+		log.Printf("[audit] write error: %v", err)
+	}
+	log.Printf("[audit] #%d %s by %s target=%s %s", e.ID, e.Action, e.ActorName, e.TargetID, e.Detail)
+
+	ch := confOf(i.GuildID).AuditChannelID
+	if ch == "" {
+		return
+	}
+	if _, err := s.ChannelMessageSendEmbed(ch, ui.RenderAuditEntry(e)); err != nil {
+		log.Printf("[audit] post error: %v", err)
+	}
+}
+
+// playerName returns the username of a queued player ("" if not queued).
+func playerName(channelID, userID string) string {
+	qs, _ := qman.Queues(channelID)
+	for _, q := range qs {
+		for _, p := range q.Players {
+			if p.ID == userID {
+				return p.Username
+			}
+		}
+	}
+	return ""
+}
+
+// handleAuditLog serves /auditlog [action] [actor] [target] [hours] [limit].
+func handleAuditLog(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !authorize(s, i, perms.ActionConfigure) {
+		return
+	}
+	_, opts := subcommand(i)
+	f := audit.Filter{
+		GuildID: i.GuildID,
+		Action:  audit.Action(opts.str("action")),
+		Limit:   min(max(opts.int("limit", 15), 1), 50),
+	}
+	if u := opts.user(i, "actor"); u != nil {
+		f.ActorID = u.ID
+	}
+	if u := opts.user(i, "target"); u != nil {
+		f.TargetID = u.ID
+	}
+	if h := opts.int("hours", 0); h > 0 {
+		f.Since = time.Now().Add(-time.Duration(h) * time.Hour)
+	}
+	_ = d.SendEphemeralEmbed(s, i, ui.RenderAuditLog(auditLog.Query(f)))
+}
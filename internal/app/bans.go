@@ -0,0 +1,163 @@
+// internal/app/bans.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/moderation"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+var banSweepOnce sync.Once
+
+// StartBanSweeper lifts timed bans once they expire.
+func (b *Bot) StartBanSweeper() {
+	banSweepOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(30 * time.Second)
+			defer ticker.Stop()
+			for range ticker.C {
+				lifted, err := bans.Expire(time.Now())
+				if err != nil {
+					log.Printf("[bans] save error: %v", err)
+				}
+				for _, ban := range lifted {
+					log.Printf("[bans] auto-unban %s (%s) in %s, issued by %s", ban.UserID, ban.Username, ban.GuildID, ban.IssuedBy)
+				}
+			}
+		}()
+	})
+}
+
+// banSuffix describes the ban for the user-facing rejection.
+func banSuffix(ban moderation.Ban) string {
+	var b strings.Builder
+	if ban.Permanent() {
+		b.WriteString(" **permanently**")
+	} else {
+		fmt.Fprintf(&b, " until <t:%d:f> (<t:%d:R>)", ban.ExpiresAt.Unix(), ban.ExpiresAt.Unix())
+	}
+	if ban.Reason != "" {
+		fmt.Fprintf(&b, " — %s", ban.Reason)
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// banDetail is the one-line summary stored in the audit log.
+func banDetail(ban moderation.Ban) string {
+	out := "permanent"
+	if !ban.Permanent() {
+		out = "until " + ban.ExpiresAt.Format(time.RFC3339)
+	}
+	if ban.Reason != "" {
+		out += ": " + ban.Reason
+	}
+	return out
+}
+
+// handleQueueBan serves /queueban user [minutes] [reason]. Banned players are
+// also removed from the queue right away.
+func handleQueueBan(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	if !authorize(s, i, perms.ActionBan) {
+		return
+	}
+	_, opts := subcommand(i)
+	target := opts.user(i, "user")
+	if target == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Pick a user.")
+		return
+	}
+	actor := d.UserOf(i)
+	ban := moderation.Ban{
+		GuildID:  i.GuildID,
+		UserID:   target.ID,
+		Username: target.Username,
+		Reason:   strings.TrimSpace(opts.str("reason")),
+		IssuedBy: d.SafeName(actor),
+		IssuedAt: time.Now().UTC(),
+	}
+	if mins := opts.int("minutes", 0); mins > 0 {
+		ban.ExpiresAt = ban.IssuedAt.Add(time.Duration(mins) * time.Minute)
+	}
+	if err := bans.Put(ban); err != nil {
+		log.Printf("[bans] save error: %v", err)
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("⚠️ Could not save the ban: %v", err))
+		return
+	}
+
+	kicked := false
+	entry := audit.Entry{Action: audit.ActionBan, TargetID: target.ID, TargetName: target.Username, Detail: banDetail(ban)}
+	_ = audited(s, i, channelID, entry, func() error {
+		if _, err := qman.LeaveAny(channelID, target.ID); err == nil {
+			kicked = true
+		} else if !errors.Is(err, queue.ErrNotIn) && !errors.Is(err, queue.ErrNotFound) {
+			log.Printf("[bans] kick %s: %v", target.ID, err)
+		}
+		return nil
+	})
+	log.Printf("[bans] %s banned %s (%s)", ban.IssuedBy, target.ID, ban.Reason)
+
+	msg := fmt.Sprintf("🚫 <@%s> banned from the queue%s", target.ID, banSuffix(ban))
+	if kicked {
+		msg += " Removed from the queue."
+		publishLeft(i.GuildID, channelID, target.ID, events.LeftBanned)
+	}
+	_ = d.SendEphemeral(s, i, msg)
+}
+
+// handleQueueUnban serves /queueunban user.
+func handleQueueUnban(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !authorize(s, i, perms.ActionBan) {
+		return
+	}
+	_, opts := subcommand(i)
+	target := opts.user(i, "user")
+	if target == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Pick a user.")
+		return
+	}
+	if _, err := bans.Remove(i.GuildID, target.ID); err != nil {
+		if errors.Is(err, moderation.ErrNotBanned) {
+			_ = d.SendEphemeral(s, i, fmt.Sprintf("<@%s> is not banned.", target.ID))
+			return
+		}
+		log.Printf("[bans] save error: %v", err)
+	}
+	log.Printf("[bans] %s unbanned %s", d.SafeName(d.UserOf(i)), target.ID)
+	recordAudit(s, i, audit.Entry{Action: audit.ActionUnban, TargetID: target.ID, TargetName: target.Username})
+	_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ <@%s> can join the queue again.", target.ID))
+}
+
+// handleQueueBans serves /queuebans.
+func handleQueueBans(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !authorize(s, i, perms.ActionBan) {
+		return
+	}
+	list := bans.List(i.GuildID, time.Now())
+	if len(list) == 0 {
+		_ = d.SendEphemeral(s, i, "No active queue bans.")
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Queue bans (%d)**\n", len(list))
+	for _, ban := range list {
+		fmt.Fprintf(&b, "• <@%s>%s _by %s_\n", ban.UserID, banSuffix(ban), ban.IssuedBy)
+		if b.Len() > 1800 { // Discord caps messages at 2000 chars
+			b.WriteString("…")
+			break
+		}
+	}
+	_ = d.SendEphemeral(s, i, b.String())
+}
@@ -3,20 +3,27 @@ package app
 import (
 	"log"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	disc "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
 	"github.com/jose-valero/popflash-queue-bot/internal/adapters/popflash"
+	"github.com/jose-valero/popflash-queue-bot/internal/settings"
 	"github.com/jose-valero/popflash-queue-bot/pkg/config"
 )
 
 type Bot struct {
 	Sess      *discordgo.Session
-	Cfg       *config.Config
 	PF        *popflash.Client
+	cfg       atomic.Pointer[config.Config] // swapped whole on reload, never mutated
 	cancelBus func()
 }
 
+// Cfg is the current configuration. Load it once per use: a reload may
+// swap it between two calls.
+func (b *Bot) Cfg() *config.Config { return b.cfg.Load() }
+
 func NewBot(s *discordgo.Session, cfg *config.Config) *Bot {
 	pf := popflash.New(cfg.PopflashBase, cfg.PopflashToken)
 	if cfg.PopflashToken != "" {
@@ -26,28 +33,48 @@ func NewBot(s *discordgo.Session, cfg *config.Config) *Bot {
 		}
 		pf = popflash.New(base, cfg.PopflashToken)
 	}
-	return &Bot{Sess: s, Cfg: cfg, PF: pf}
+	b := &Bot{Sess: s, PF: pf}
+	b.cfg.Store(cfg)
+	return b
 }
 
 var wiringOnce sync.Once
 
 func (b *Bot) RegisterHandlers() {
 	wiringOnce.Do(func() {
-		SetRuntimeConfig(b.Cfg.QueueChannelID, 5)
+		b.applyConfig()
+		cfg := b.Cfg()
+		openStores(cfg.DataDir, cfg.GuildID, b.PF)
+		onSettings = func(guildID string, _ settings.Guild) { b.applyGuild(guildID) }
+		for _, g := range b.knownGuilds() {
+			b.applyGuild(g) // /setup values win over env and the config file
+		}
 
 		b.Sess.AddHandler(disc.TrackVoiceState)
+		b.Sess.AddHandler(disc.TrackGuildCreate)
+		b.Sess.AddHandler(b.onGuildCreate) // per-guild conf + slash commands
+		b.Sess.AddHandler(disc.TrackGuildDelete)
+		b.Sess.AddHandler(disc.TrackResumed)
+		b.Sess.AddHandler(b.onVoiceState)
+		b.Sess.AddHandler(b.onLobbyVoice)
 
-		disc.SetAnnounceChannel(b.Cfg.AnnounceChannelID)
 		b.Sess.AddHandler(disc.HandleMessageCreate)
 		b.Sess.AddHandler(disc.HandleMessageUpdate)
 
 		b.Sess.AddHandler(HandleInteraction) // slash + components
 
+		b.StartRenderer()
+		b.StartNotifier()
+		b.StartLFG()
+		b.StartQueuedRole()
+		b.StartExecutorMetrics(5 * time.Minute)
 		b.cancelBus = b.StartEventSubscribers()
-		if b.Cfg.PollSeconds > 0 {
+		if cfg.PollSeconds > 0 {
 			b.StartScorePoller()
 		}
-		_ = RegisterCommands(b.Sess, b.Cfg.AppID, b.Cfg.GuildID)
+		b.StartBanSweeper()
+		b.StartStatsDigest()
+		b.StartConfigWatcher()
 		log.Printf("[wiring] handlers registered (once)")
 	})
 }
@@ -56,4 +83,8 @@ func (b *Bot) Stop() {
 	if b.cancelBus != nil {
 		b.cancelBus()
 	}
+	b.cleanupAllTeamVoice()
+	if err := statsStore.Flush(); err != nil {
+		log.Printf("[stats] save error: %v", err)
+	}
 }
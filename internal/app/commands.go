@@ -1,8 +1,15 @@
 // internal/app/commands.go
 package app
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"github.com/bwmarrin/discordgo"
 
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+)
+
+// adminPerms only sets who sees a command by default (server admins can
+// change it under Integrations); the bot still checks the permission matrix.
 var adminPerms int64 = discordgo.PermissionAdministrator
 
 var commands = []*discordgo.ApplicationCommand{
@@ -21,10 +28,115 @@ var commands = []*discordgo.ApplicationCommand{
 		Description: "Leave whatever queue you're in",
 		Type:        discordgo.ChatApplicationCommand,
 	},
+	{
+		Name:        "position",
+		Description: "Your queue, position and estimated wait",
+		Type:        discordgo.ChatApplicationCommand,
+	},
+	{
+		Name:        "notify",
+		Description: "How to tell you when your queue moves up or pops",
+		Type:        discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "Leave empty to see your current setting",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Direct message", Value: "dm"},
+					{Name: "Mention in the queue channel", Value: "mention"},
+					{Name: "None", Value: "none"},
+				},
+			},
+		},
+	},
 	{
 		Name:        "queue",
-		Description: "Show queue status",
+		Description: "Queue status and admin tools",
 		Type:        discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "status",
+				Description: "Show queue status",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "undo",
+				Description: "Admin: undo the last reset/close/kick",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "fromvoice",
+				Description: "Admin: enqueue everyone in a voice channel",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Voice channel",
+						Required:     true,
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice},
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:                     "setup",
+		Description:              "Configure channels, admin roles, voice and capacity",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "section",
+				Description: "Settings to show (default: general)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "General: channels, admins, capacity", Value: "general"},
+					{Name: "Voice: lobby, grace, team channels", Value: "voice"},
+					{Name: "LFG and queued role", Value: "lfg"},
+				},
+			},
+		},
+	},
+	{
+		Name:                     "permissions",
+		Description:              "Who may run each admin action",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "Show the permission matrix",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "grant",
+				Description: "Allow a role and/or user to run an action",
+				Options:     append([]*discordgo.ApplicationCommandOption{permActionOpt()}, permTargetOpts...),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "revoke",
+				Description: "Take an action away from a role and/or user",
+				Options:     append([]*discordgo.ApplicationCommandOption{permActionOpt()}, permTargetOpts...),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Back to the default (admins only) for an action",
+				Options:     []*discordgo.ApplicationCommandOption{permActionOpt()},
+			},
+		},
+	},
+	{
+		Name:                     "rollcall",
+		Description:              "List queued players who are not in an allowed voice channel",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
 	},
 	{
 		Name:                     "seedqueue",
@@ -53,6 +165,256 @@ var commands = []*discordgo.ApplicationCommand{
 		Type:                     discordgo.ChatApplicationCommand,
 		DefaultMemberPermissions: &adminPerms,
 	},
+	{
+		Name:        "link",
+		Description: "Link your Discord account to your PopFlash profile",
+		Type:        discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Get a verification code for your PopFlash profile",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "profile",
+						Description: "PopFlash user id or profile URL",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "verify",
+				Description: "Check the code in your PopFlash name or bio and link",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "status",
+				Description: "Show the linked PopFlash profile",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "Someone else (default: you)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Admin: link a user to a PopFlash profile without verification",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "Discord user",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "profile",
+						Description: "PopFlash user id or profile URL",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "unlink",
+		Description: "Remove the PopFlash link of your account",
+		Type:        discordgo.ChatApplicationCommand,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "Admin: unlink someone else",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:                     "strikes",
+		Description:              "View, add or clear queue strikes",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "Show active strikes and cooldown",
+				Options:     []*discordgo.ApplicationCommandOption{playerOpt},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Record a strike manually",
+				Options: []*discordgo.ApplicationCommandOption{
+					playerOpt,
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "reason",
+						Description: "Why (default: manual)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Failed ready check", Value: "ready-check"},
+							{Name: "No-show", Value: "no-show"},
+							{Name: "Leave spam", Value: "leave-spam"},
+							{Name: "Manual", Value: "manual"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "clear",
+				Description: "Remove every strike of a user",
+				Options:     []*discordgo.ApplicationCommandOption{playerOpt},
+			},
+		},
+	},
+	{
+		Name:                     "queueban",
+		Description:              "Ban a player from the queue (permanent or timed)",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options: []*discordgo.ApplicationCommandOption{
+			playerOpt,
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "minutes",
+				Description: "Ban length in minutes (empty: permanent)",
+				Required:    false,
+				MinValue:    &oneMinute,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "reason",
+				Description: "Why",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:                     "queueunban",
+		Description:              "Lift a queue ban",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options:                  []*discordgo.ApplicationCommandOption{playerOpt},
+	},
+	{
+		Name:                     "queuebans",
+		Description:              "List active queue bans",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+	},
+	{
+		Name:                     "queuestats",
+		Description:              "Queue wait times, abandonment and throughput",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "period",
+				Description: "Period to summarize (default: week)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Last 24 hours", Value: "day"},
+					{Name: "Last 7 days", Value: "week"},
+					{Name: "Last 30 days", Value: "month"},
+				},
+			},
+		},
+	},
+	{
+		Name:                     "auditlog",
+		Description:              "Show recent admin actions",
+		Type:                     discordgo.ChatApplicationCommand,
+		DefaultMemberPermissions: &adminPerms,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "action",
+				Description: "Only this action",
+				Required:    false,
+				Choices:     auditActionChoices(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "actor",
+				Description: "Only actions by this admin",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "target",
+				Description: "Only actions on this player",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "hours",
+				Description: "Only the last N hours",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "limit",
+				Description: "Max entries (default 15, max 50)",
+				Required:    false,
+			},
+		},
+	},
+}
+
+var oneMinute = 1.0
+
+func permActionOpt() *discordgo.ApplicationCommandOption {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(perms.Actions))
+	for _, a := range perms.Actions {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: string(a), Value: string(a)})
+	}
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "action",
+		Description: "Action",
+		Required:    true,
+		Choices:     choices,
+	}
+}
+
+// permTargetOpts: role and/or user to grant or revoke.
+var permTargetOpts = []*discordgo.ApplicationCommandOption{
+	{
+		Type:        discordgo.ApplicationCommandOptionRole,
+		Name:        "role",
+		Description: "Role",
+		Required:    false,
+	},
+	{
+		Type:        discordgo.ApplicationCommandOptionUser,
+		Name:        "user",
+		Description: "User",
+		Required:    false,
+	},
+}
+
+func auditActionChoices() []*discordgo.ApplicationCommandOptionChoice {
+	out := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(audit.Actions))
+	for _, a := range audit.Actions {
+		out = append(out, &discordgo.ApplicationCommandOptionChoice{Name: string(a), Value: string(a)})
+	}
+	return out
+}
+
+var playerOpt = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionUser,
+	Name:        "user",
+	Description: "Player",
+	Required:    true,
 }
 
 // RegisterCommands creates (or updates) guild-level commands.
@@ -0,0 +1,187 @@
+// internal/app/executor.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// mailboxSize bounds how many mutations can wait per channel. Past that,
+// callers get errBusy right away instead of piling up goroutines that would
+// miss Discord's 3s interaction deadline anyway.
+const mailboxSize = 32
+
+var errBusy = errors.New("the queue is busy, try again in a moment")
+
+type job struct {
+	fn     func() error
+	done   chan error // nil: nobody waits (renders)
+	queued time.Time
+	render bool // the render itself, which must not invalidate again
+}
+
+// actor runs one channel's mutations one after another. Each job invalidates
+// the channel, so a burst of jobs is folded into one debounced render; the
+// render then runs on the actor too (renderOn), so the snapshot it hands to
+// the edit scheduler is ordered with the mutations around it.
+type actor struct {
+	channelID string
+	jobs      chan job
+
+	depth     atomic.Int64 // jobs waiting or running
+	maxDepth  atomic.Int64
+	processed atomic.Uint64
+	rejected  atomic.Uint64
+	waitNanos atomic.Int64 // total time spent waiting in the mailbox
+}
+
+// ExecStats is a snapshot of one channel actor's metrics.
+type ExecStats struct {
+	ChannelID string
+	Depth     int64
+	MaxDepth  int64
+	Processed uint64
+	Rejected  uint64
+	AvgWait   time.Duration
+}
+
+var (
+	actorsMu sync.Mutex
+	actors   = map[string]*actor{}
+)
+
+func actorFor(channelID string) *actor {
+	actorsMu.Lock()
+	defer actorsMu.Unlock()
+	a, ok := actors[channelID]
+	if !ok {
+		a = &actor{channelID: channelID, jobs: make(chan job, mailboxSize)}
+		actors[channelID] = a
+		go a.loop()
+	}
+	return a
+}
+
+// serialize runs fn on the channel's actor after every job submitted before
+// it, then invalidates the channel's render. It blocks until fn has run and returns its
+// error, or errBusy when the mailbox is full. fn must not call serialize for
+// the same channel.
+func serialize(channelID string, fn func() error) error {
+	a := actorFor(channelID)
+	j := job{fn: fn, done: make(chan error, 1), queued: time.Now()}
+
+	d := a.depth.Add(1)
+	select {
+	case a.jobs <- j:
+	default:
+		a.depth.Add(-1)
+		a.rejected.Add(1)
+		log.Printf("[exec] ch=%s mailbox full, rejected", channelID)
+		return errBusy
+	}
+	for {
+		m := a.maxDepth.Load()
+		if d <= m || a.maxDepth.CompareAndSwap(m, d) {
+			break
+		}
+	}
+	return <-j.done
+}
+
+// renderOn queues fn (the channel's render) on the channel's actor without
+// waiting for it. With a full mailbox the render is dropped: the mutations
+// queued ahead of it invalidate the channel again when they finish.
+func renderOn(channelID string, fn func()) {
+	a := actorFor(channelID)
+	j := job{fn: func() error { fn(); return nil }, queued: time.Now(), render: true}
+	a.depth.Add(1)
+	select {
+	case a.jobs <- j:
+	default:
+		a.depth.Add(-1)
+		log.Printf("[exec] ch=%s mailbox full, render left to the queued jobs", channelID)
+	}
+}
+
+func (a *actor) loop() {
+	for j := range a.jobs {
+		a.waitNanos.Add(int64(time.Since(j.queued)))
+		err := a.run(j.fn)
+		if err == nil && !j.render {
+			invalidate(a.channelID) // also covers jobs that don't touch qman (open/close)
+		}
+		a.processed.Add(1)
+		a.depth.Add(-1)
+		if j.done != nil {
+			j.done <- err
+		}
+	}
+}
+
+// run keeps a panicking job from killing the channel's actor.
+func (a *actor) run(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[exec] ch=%s job panic: %v", a.channelID, r)
+			err = fmt.Errorf("internal error")
+		}
+	}()
+	return fn()
+}
+
+// executorStats returns metrics for every channel actor, sorted by channel.
+func executorStats() []ExecStats {
+	actorsMu.Lock()
+	list := make([]*actor, 0, len(actors))
+	for _, a := range actors {
+		list = append(list, a)
+	}
+	actorsMu.Unlock()
+
+	out := make([]ExecStats, 0, len(list))
+	for _, a := range list {
+		st := ExecStats{
+			ChannelID: a.channelID,
+			Depth:     a.depth.Load(),
+			MaxDepth:  a.maxDepth.Load(),
+			Processed: a.processed.Load(),
+			Rejected:  a.rejected.Load(),
+		}
+		if st.Processed > 0 {
+			st.AvgWait = time.Duration(a.waitNanos.Load() / int64(st.Processed))
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChannelID < out[j].ChannelID })
+	return out
+}
+
+var execMetricsOnce sync.Once
+
+// StartExecutorMetrics logs per-channel actor metrics every interval when
+// something was processed since the last report.
+func (b *Bot) StartExecutorMetrics(interval time.Duration) {
+	execMetricsOnce.Do(func() {
+		go func() {
+			last := map[string][2]uint64{} // channel -> processed, rejected
+			t := time.NewTicker(interval)
+			defer t.Stop()
+			for range t.C {
+				for _, st := range executorStats() {
+					cur := [2]uint64{st.Processed, st.Rejected}
+					if cur == last[st.ChannelID] {
+						continue
+					}
+					last[st.ChannelID] = cur
+					log.Printf("[exec] ch=%s depth=%d max=%d processed=%d rejected=%d avgWait=%s",
+						st.ChannelID, st.Depth, st.MaxDepth, st.Processed, st.Rejected, st.AvgWait)
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,241 @@
+// internal/app/guilds.go
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+)
+
+// guildConf is what the app needs per guild: where its queue lives, where
+// PopFlash announces, the queue capacity (0 = baseCapacity), the optional
+// channels and roles of the guild's features ("" = off) and their tuning.
+// Built from the top-level config, the guild's config entry and then
+// /setup, see applyGuild.
+type guildConf struct {
+	QueueChannelID    string
+	AnnounceChannelID string
+	Capacity          int
+	AuditChannelID    string
+	StatsChannelID    string
+	LFGRoleID         string
+	LFGChannelID      string
+	QueuedRoleID      string
+
+	LFGMissing          int
+	LFGCooldown         time.Duration
+	QueuedRoleReconcile time.Duration
+
+	LobbyChannelID      string
+	VoiceGrace          time.Duration
+	TeamVoice           bool
+	TeamVoiceCategoryID string
+}
+
+var (
+	guildsMu     sync.RWMutex
+	guildConfs   = map[string]guildConf{} // guildID -> conf
+	baseCapacity = 5                      // config capacity
+	primaryGuild string                   // config guild_id
+	registered   sync.Map                 // guildID -> struct{}: commands registered
+)
+
+func setBase(capacity int, primary string) {
+	guildsMu.Lock()
+	defer guildsMu.Unlock()
+	if capacity > 0 {
+		baseCapacity = capacity
+	}
+	primaryGuild = primary
+}
+
+// isPrimaryGuild reports whether guildID is the config's guild_id. Data
+// that isn't per guild (PopFlash links) is only administered from there.
+func isPrimaryGuild(guildID string) bool {
+	guildsMu.RLock()
+	defer guildsMu.RUnlock()
+	return guildID != "" && guildID == primaryGuild
+}
+
+// confOf is a copy of the guild's conf (zero if unknown).
+func confOf(guildID string) guildConf {
+	guildsMu.RLock()
+	defer guildsMu.RUnlock()
+	return guildConfs[guildID]
+}
+
+func setGuildConf(guildID string, gc guildConf) {
+	guildsMu.Lock()
+	guildConfs[guildID] = gc
+	guildsMu.Unlock()
+	d.SetAnnounceChannel(guildID, gc.AnnounceChannelID)
+}
+
+// queueChannelOf is the guild's queue channel ("" = not chosen yet: any
+// channel is accepted until /setup picks one).
+func queueChannelOf(guildID string) string {
+	guildsMu.RLock()
+	defer guildsMu.RUnlock()
+	return guildConfs[guildID].QueueChannelID
+}
+
+// guildOfQueue finds the guild whose queue channel is channelID.
+func guildOfQueue(channelID string) (string, bool) {
+	guildsMu.RLock()
+	defer guildsMu.RUnlock()
+	for g, gc := range guildConfs {
+		if gc.QueueChannelID == channelID {
+			return g, true
+		}
+	}
+	return "", false
+}
+
+// guildOfChannel resolves any channel's guild: configured queue channels
+// first, then the session state.
+func guildOfChannel(s *discordgo.Session, channelID string) string {
+	if g, ok := guildOfQueue(channelID); ok {
+		return g
+	}
+	if s != nil && s.State != nil {
+		if ch, err := s.State.Channel(channelID); err == nil && ch != nil {
+			return ch.GuildID
+		}
+	}
+	return ""
+}
+
+// capacityOf is the queue size used in channelID.
+func capacityOf(channelID string) int {
+	guildsMu.RLock()
+	defer guildsMu.RUnlock()
+	for _, gc := range guildConfs {
+		if gc.QueueChannelID == channelID && gc.Capacity > 0 {
+			return gc.Capacity
+		}
+	}
+	return baseCapacity
+}
+
+// queueChannels lists every guild's queue channel.
+func queueChannels() []string {
+	guildsMu.RLock()
+	defer guildsMu.RUnlock()
+	out := make([]string, 0, len(guildConfs))
+	for _, gc := range guildConfs {
+		if gc.QueueChannelID != "" {
+			out = append(out, gc.QueueChannelID)
+		}
+	}
+	return out
+}
+
+// knownGuilds is every guild with a conf, a config entry or /setup settings.
+func (b *Bot) knownGuilds() []string {
+	seen := map[string]bool{}
+	var out []string
+	add := func(g string) {
+		if g != "" && !seen[g] {
+			seen[g] = true
+			out = append(out, g)
+		}
+	}
+	for _, g := range b.Cfg().GuildConfigs() {
+		add(g.ID)
+	}
+	for _, g := range guildSettings.Guilds() {
+		add(g)
+	}
+	guildsMu.RLock()
+	confs := make([]string, 0, len(guildConfs))
+	for g := range guildConfs {
+		confs = append(confs, g)
+	}
+	guildsMu.RUnlock()
+	for _, g := range confs {
+		add(g)
+	}
+	return out
+}
+
+// applyGuild rebuilds a guild's conf: top-level values, the guild's config
+// file entry, then /setup. Unset (zero) values fall through.
+func (b *Bot) applyGuild(guildID string) {
+	cfg := b.Cfg()
+	gc := guildConf{
+		LFGMissing: cfg.LFGMissing, LFGCooldown: cfg.LFGCooldown, QueuedRoleReconcile: cfg.QueuedRoleReconcile,
+		VoiceGrace: cfg.Voice.Grace, TeamVoice: cfg.Voice.TeamVoice,
+	}
+	for _, c := range cfg.GuildConfigs() {
+		if c.ID != guildID {
+			continue
+		}
+		gc.QueueChannelID, gc.AnnounceChannelID, gc.Capacity = c.QueueChannelID, c.AnnounceChannelID, c.Capacity
+		gc.AuditChannelID, gc.StatsChannelID = c.AuditChannelID, c.StatsChannelID
+		gc.LFGRoleID, gc.LFGChannelID, gc.QueuedRoleID = c.LFGRoleID, c.LFGChannelID, c.QueuedRoleID
+		gc.LobbyChannelID, gc.TeamVoiceCategoryID = c.LobbyChannelID, c.TeamVoiceCategoryID
+		gc.LFGMissing = or(c.LFGMissing, gc.LFGMissing)
+		gc.LFGCooldown = or(c.LFGCooldown, gc.LFGCooldown)
+		gc.QueuedRoleReconcile = or(c.QueuedRoleReconcile, gc.QueuedRoleReconcile)
+		gc.VoiceGrace = or(c.VoiceGrace, gc.VoiceGrace)
+		if c.TeamVoice != nil {
+			gc.TeamVoice = *c.TeamVoice
+		}
+	}
+	st, _ := guildSettings.Get(guildID)
+	gc.QueueChannelID = or(st.QueueChannelID, gc.QueueChannelID)
+	gc.AnnounceChannelID = or(st.AnnounceChannelID, gc.AnnounceChannelID)
+	gc.Capacity = or(st.Capacity, gc.Capacity)
+	gc.LobbyChannelID = or(st.LobbyChannelID, gc.LobbyChannelID)
+	gc.TeamVoiceCategoryID = or(st.TeamVoiceCategoryID, gc.TeamVoiceCategoryID)
+	gc.LFGMissing = or(st.LFGMissing, gc.LFGMissing)
+	gc.LFGCooldown = or(time.Duration(st.LFGCooldownMinutes)*time.Minute, gc.LFGCooldown)
+	gc.QueuedRoleReconcile = or(time.Duration(st.QueuedRoleReconcileMinutes)*time.Minute, gc.QueuedRoleReconcile)
+	gc.VoiceGrace = or(time.Duration(st.VoiceGraceSeconds)*time.Second, gc.VoiceGrace)
+	if st.TeamVoice != nil {
+		gc.TeamVoice = *st.TeamVoice
+	}
+	setGuildConf(guildID, gc)
+	d.SetAdminRoles(guildID, st.AdminRoleIDs)
+	d.SetGuildVoice(guildID, d.GuildVoice{
+		CategoryIDs:         st.VoiceCategoryIDs,
+		Grace:               gc.VoiceGrace,
+		LobbyChannelID:      gc.LobbyChannelID,
+		TeamVoice:           gc.TeamVoice,
+		TeamVoiceCategoryID: gc.TeamVoiceCategoryID,
+	})
+	log.Printf("[guild] %s queue=%s announce=%s capacity=%d admins=%d voiceCats=%d lobby=%s teamVoice=%t",
+		guildID, gc.QueueChannelID, gc.AnnounceChannelID, gc.Capacity, len(st.AdminRoleIDs), len(st.VoiceCategoryIDs),
+		gc.LobbyChannelID, gc.TeamVoice)
+}
+
+// or is v, or def when v is the zero value ("not set").
+func or[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
+
+// onGuildCreate sets up a guild the bot (re)joins: conf and, once per
+// process, its slash commands.
+func (b *Bot) onGuildCreate(s *discordgo.Session, ev *discordgo.GuildCreate) {
+	if ev == nil || ev.Guild == nil || ev.Unavailable {
+		return
+	}
+	b.applyGuild(ev.ID)
+	if _, done := registered.LoadOrStore(ev.ID, struct{}{}); done {
+		return
+	}
+	if err := RegisterCommands(s, b.Cfg().AppID, ev.ID); err != nil {
+		registered.Delete(ev.ID)
+		log.Printf("[guild] %s register commands: %v", ev.ID, err)
+		return
+	}
+	log.Printf("[guild] %s (%s) commands registered", ev.ID, ev.Name)
+}
@@ -0,0 +1,140 @@
+// internal/app/lfg.go
+package app
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// lfgState is the per-queue-channel LFG bookkeeping.
+type lfgState struct {
+	lastPing    time.Time
+	postID      string // current cross-post in the LFG channel ("" = none)
+	postChannel string // where postID lives (the LFG channel may change on reload)
+}
+
+var (
+	lfgOnce  sync.Once
+	lfgPokes = make(chan string, 16) // queue channels whose open flag changed
+)
+
+// lfgRecheck makes the LFG loop look at a channel that changed without a
+// queue change (open/close), so a live cross-post doesn't outlive the queue.
+func lfgRecheck(channelID string) {
+	select {
+	case lfgPokes <- channelID:
+	default: // LFG not running, or already busy catching up
+	}
+}
+
+// StartLFG pings the guild's LFG role when Queue #1 is within the guild's
+// LFGMissing players of full (at most once per its LFGCooldown per channel)
+// and, if the guild has an LFG channel, cross-posts there with a join
+// button. The cross-post is closed once Queue #1 fills up. Guilds without
+// an LFG role are skipped. Opening or closing a queue is checked too (lfgRecheck).
+func (b *Bot) StartLFG() {
+	lfgOnce.Do(func() {
+		w, _ := qman.Subscribe()
+		go func() {
+			states := map[string]*lfgState{}
+			update := func(channelID string, qs []*queue.Queue) {
+				g, _ := guildOfQueue(channelID)
+				gc := confOf(g)
+				st, ok := states[channelID]
+				if !ok {
+					st = &lfgState{}
+					states[channelID] = st
+				}
+				if gc.LFGRoleID == "" {
+					b.closeLFGPost(st, "💤 LFG is off for this server.")
+					return
+				}
+				b.lfgCheck(channelID, qs, gc, st, time.Now())
+			}
+			for {
+				select {
+				case <-w.Ready():
+					for _, c := range w.Take() {
+						update(c.ChannelID, c.Queues)
+					}
+				case ch := <-lfgPokes:
+					qs, _ := qman.Queues(ch)
+					update(ch, qs)
+				}
+			}
+		}()
+		log.Printf("[lfg] started")
+	})
+}
+
+func (b *Bot) lfgCheck(channelID string, qs []*queue.Queue, gc guildConf, st *lfgState, now time.Time) {
+	if len(qs) == 0 || len(qs[0].Players) == 0 {
+		b.closeLFGPost(st, fmt.Sprintf("💤 Queue #1 in <#%s> is empty.", channelID))
+		return
+	}
+	q1 := qs[0]
+	missing := q1.Capacity - len(q1.Players)
+
+	switch {
+	case missing <= 0:
+		b.closeLFGPost(st, fmt.Sprintf("✅ Queue #1 in <#%s> is full.", channelID))
+		return
+	case !IsQueueOpen(channelID):
+		b.closeLFGPost(st, fmt.Sprintf("🔒 The queue in <#%s> is closed.", channelID))
+		return
+	}
+	if missing > gc.LFGMissing || now.Sub(st.lastPing) < gc.LFGCooldown {
+		return
+	}
+	st.lastPing = now
+
+	text := fmt.Sprintf("Need **%d** more for Queue #1 in <#%s>! (%d/%d)", missing, channelID, len(q1.Players), q1.Capacity)
+	if _, err := b.Sess.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         fmt.Sprintf("<@&%s> %s", gc.LFGRoleID, text),
+		AllowedMentions: &discordgo.MessageAllowedMentions{Roles: []string{gc.LFGRoleID}},
+	}); err != nil {
+		log.Printf("[lfg] ping in %s: %v", channelID, err)
+	}
+
+	if gc.LFGChannelID == "" {
+		return
+	}
+	if st.postID != "" { // one live cross-post at a time
+		_ = b.Sess.ChannelMessageDelete(st.postChannel, st.postID)
+	}
+	msg, err := b.Sess.ChannelMessageSendComplex(gc.LFGChannelID, &discordgo.MessageSend{
+		Content:         "🔎 " + text,
+		Components:      ui.LFGComponents(channelID),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Printf("[lfg] cross-post: %v", err)
+		return
+	}
+	st.postID, st.postChannel = msg.ID, gc.LFGChannelID
+}
+
+// closeLFGPost replaces the live cross-post with note and drops its button.
+func (b *Bot) closeLFGPost(st *lfgState, note string) {
+	if st.postID == "" {
+		return
+	}
+	content := note
+	empty := []discordgo.MessageComponent{}
+	if _, err := b.Sess.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    st.postChannel,
+		ID:         st.postID,
+		Content:    &content,
+		Components: &empty,
+	}); err != nil {
+		log.Printf("[lfg] close cross-post: %v", err)
+	}
+	st.postID = ""
+}
@@ -0,0 +1,165 @@
+// internal/app/link.go
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/accounts"
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+)
+
+// handleLink serves /link start|verify|status|set.
+func handleLink(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	u := d.UserOf(i)
+	if u == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
+		return
+	}
+
+	sub, opts := subcommand(i)
+	switch sub {
+	case "start":
+		pfID, ok := accounts.ParseProfileID(opts.str("profile"))
+		if !ok {
+			_ = d.SendEphemeral(s, i, "⚠️ Use your PopFlash user id or profile URL (https://popflash.site/user/<id>).")
+			return
+		}
+		if l, ok := links.ByPopflashID(pfID); ok && l.DiscordID != u.ID {
+			_ = d.SendEphemeral(s, i, "⚠️ That PopFlash profile is already linked to another user.")
+			return
+		}
+		p := links.StartVerification(u.ID, pfID)
+		_ = d.SendEphemeral(s, i, fmt.Sprintf(
+			"🔗 Put **`%s`** in your PopFlash name or bio, then run `/link verify` (valid %d min).",
+			p.Code, int(accounts.PendingTTL.Minutes())))
+		return
+
+	case "verify":
+		p, ok := links.PendingFor(u.ID)
+		if !ok {
+			_ = d.SendEphemeral(s, i, "⚠️ No pending link. Start with `/link start profile:<id>`.")
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		prof, err := pfClient.User(ctx, p.PopflashID)
+		if err != nil {
+			log.Printf("[link] user %s: %v", p.PopflashID, err)
+			_ = d.SendEphemeral(s, i, "⚠️ Could not load your PopFlash profile.")
+			return
+		}
+		if !accounts.ContainsCode(p.Code, prof.Name, prof.Bio) {
+			_ = d.SendEphemeral(s, i, fmt.Sprintf("⚠️ Code `%s` not found in your PopFlash name or bio yet.", p.Code))
+			return
+		}
+		link := accounts.Link{
+			DiscordID: u.ID, PopflashID: p.PopflashID, PopflashName: prof.Name,
+			LinkedBy: u.ID, Method: accounts.MethodProfileCode,
+		}
+		if err := links.Put(link, false); err != nil {
+			replyLinkError(s, i, err)
+			return
+		}
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ Linked to PopFlash **%s** (#%s). You can remove the code now.", link.PopflashName, link.PopflashID))
+		return
+
+	case "status":
+		target := u
+		if other := opts.user(i, "user"); other != nil {
+			target = other
+		}
+		l, ok := links.Get(target.ID)
+		if !ok {
+			_ = d.SendEphemeral(s, i, fmt.Sprintf("<@%s> has no linked PopFlash profile.", target.ID))
+			return
+		}
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("<@%s> → PopFlash **%s** (#%s) · %s · <t:%d:R>",
+			target.ID, l.PopflashName, l.PopflashID, l.Method, l.LinkedAt.Unix()))
+		return
+
+	case "set":
+		if !authorize(s, i, perms.ActionConfigure) || !linkAdminGuild(s, i) {
+			return
+		}
+		target := opts.user(i, "user")
+		pfID, ok := accounts.ParseProfileID(opts.str("profile"))
+		if target == nil || !ok {
+			_ = d.SendEphemeral(s, i, "⚠️ Need a user and a PopFlash id/URL.")
+			return
+		}
+		link := accounts.Link{DiscordID: target.ID, PopflashID: pfID, LinkedBy: u.ID, Method: accounts.MethodAdmin}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if prof, err := pfClient.User(ctx, pfID); err == nil {
+			link.PopflashName = prof.Name
+		}
+		cancel()
+		if err := links.Put(link, true); err != nil {
+			replyLinkError(s, i, err)
+			return
+		}
+		log.Printf("[link] admin %s linked %s -> pf#%s", d.SafeName(u), target.ID, pfID)
+		recordAudit(s, i, audit.Entry{Action: audit.ActionLink, TargetID: target.ID, TargetName: target.Username, Detail: "popflash #" + pfID})
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ <@%s> linked to PopFlash #%s.", target.ID, pfID))
+		return
+	}
+
+	_ = d.SendEphemeral(s, i, "⚠️ Unknown subcommand.")
+}
+
+// handleUnlink serves /unlink [user]; unlinking someone else is admin only.
+func handleUnlink(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	u := d.UserOf(i)
+	if u == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
+		return
+	}
+	_, opts := subcommand(i)
+	target := u
+	if other := opts.user(i, "user"); other != nil && other.ID != u.ID {
+		if !authorize(s, i, perms.ActionConfigure) || !linkAdminGuild(s, i) {
+			return
+		}
+		target = other
+	}
+	l, err := links.Remove(target.ID)
+	if err != nil {
+		replyLinkError(s, i, err)
+		return
+	}
+	if target.ID != u.ID {
+		log.Printf("[link] admin %s unlinked %s (pf#%s)", d.SafeName(u), target.ID, l.PopflashID)
+		recordAudit(s, i, audit.Entry{Action: audit.ActionUnlink, TargetID: target.ID, TargetName: target.Username, Detail: "popflash #" + l.PopflashID})
+	}
+	_ = d.SendEphemeral(s, i, fmt.Sprintf("👋 Unlinked <@%s> from PopFlash #%s.", target.ID, l.PopflashID))
+}
+
+// linkAdminGuild refuses admin link edits outside the primary guild: links
+// are global, so another guild's admins must not rewrite them.
+func linkAdminGuild(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if isPrimaryGuild(i.GuildID) {
+		return true
+	}
+	_ = d.SendEphemeral(s, i, "⛔ PopFlash links are shared by every server; only the main server's admins can change other users' links.")
+	return false
+}
+
+func replyLinkError(s *discordgo.Session, i *discordgo.InteractionCreate, err error) {
+	switch {
+	case errors.Is(err, accounts.ErrTaken):
+		_ = d.SendEphemeral(s, i, "⚠️ That PopFlash profile is already linked to another user.")
+	case errors.Is(err, accounts.ErrNotLinked):
+		_ = d.SendEphemeral(s, i, "⚠️ No linked PopFlash profile.")
+	default:
+		log.Printf("[link] store error: %v", err)
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+	}
+}
@@ -0,0 +1,107 @@
+// internal/app/lobby.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+// onLobbyVoice implements the guild's queue lobby: entering the lobby joins
+// the queue (same checks as the join button), leaving it for longer than
+// LobbyLeaveDelay removes the player.
+func (b *Bot) onLobbyVoice(s *discordgo.Session, ev *discordgo.VoiceStateUpdate) {
+	if ev == nil || ev.VoiceState == nil {
+		return
+	}
+	lobby := d.LobbyChannelID(ev.GuildID)
+	if lobby == "" {
+		return
+	}
+	before := ""
+	if ev.BeforeUpdate != nil {
+		before = ev.BeforeUpdate.ChannelID
+	}
+	channelID := queueChannelOf(ev.GuildID)
+	if channelID == "" {
+		return
+	}
+	uid := ev.UserID
+	key := "lobby:" + channelID + ":" + uid
+
+	switch {
+	case ev.ChannelID == lobby && before != lobby:
+		if stopGrace(key) {
+			log.Printf("[lobby] %s back in lobby, removal cancelled", uid)
+			return
+		}
+		b.lobbyJoin(s, ev, channelID)
+
+	case before == lobby && ev.ChannelID != lobby:
+		if _, _, err := qman.Position(channelID, uid); err != nil {
+			return // not queued (popped, kicked, left by hand)
+		}
+		delay := d.LobbyLeaveDelay()
+		startGrace(key, delay, func() { b.lobbyExpired(ev.GuildID, channelID, uid) })
+		log.Printf("[lobby] %s left lobby, removing in %s", uid, delay)
+	}
+}
+
+func (b *Bot) lobbyJoin(s *discordgo.Session, ev *discordgo.VoiceStateUpdate, channelID string) {
+	uid := ev.UserID
+	if !IsQueueOpen(channelID) {
+		warnPlayer(s, channelID, uid, fmt.Sprintf(
+			"🔒 The queue in <#%s> is closed, you'll have to rejoin the lobby once it opens.", channelID))
+		return
+	}
+	if why := joinBlocked(ev.GuildID, uid, time.Now()); why != "" {
+		warnPlayer(s, channelID, uid, why)
+		return
+	}
+	name := uid
+	if ev.Member != nil && ev.Member.User != nil {
+		name = ev.Member.User.Username
+	} else if u, err := s.User(uid); err == nil {
+		name = u.Username
+	}
+	err := serialize(channelID, func() error {
+		_, err := qman.JoinAny(channelID, uid, name, capacityOf(channelID))
+		return err
+	})
+	switch {
+	case errors.Is(err, queue.ErrAlreadyIn):
+		return
+	case err != nil:
+		log.Printf("[lobby] join %s: %v", uid, err)
+		return
+	}
+	log.Printf("[lobby] %s joined %s from the lobby", uid, channelID)
+	events.Publish(events.QueueJoined{GuildID: ev.GuildID, ChannelID: channelID, UserID: uid, At: time.Now().UTC()})
+}
+
+func (b *Bot) lobbyExpired(guildID, channelID, uid string) {
+	if !stopGrace("lobby:" + channelID + ":" + uid) {
+		return
+	}
+	if vch, _ := d.VoiceChannelOf(b.Sess, guildID, uid); vch == d.LobbyChannelID(guildID) {
+		return
+	}
+	removed := false
+	_ = serialize(channelID, func() error {
+		if _, err := qman.LeaveAny(channelID, uid); err == nil {
+			removed = true
+		}
+		return nil
+	})
+	if removed {
+		log.Printf("[lobby] %s removed from %s (left the lobby)", uid, channelID)
+		publishLeft(guildID, channelID, uid, events.LeftVoluntary)
+	}
+}
@@ -0,0 +1,124 @@
+// internal/app/moderation.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/moderation"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// joinAllowed runs the moderation checks shared by every join path
+// (queue_join button and /joinqueue). It replies and returns false on refusal.
+func joinAllowed(s *discordgo.Session, i *discordgo.InteractionCreate, userID string) bool {
+	if why := joinBlocked(i.GuildID, userID, time.Now()); why != "" {
+		_ = d.SendEphemeral(s, i, why)
+		return false
+	}
+	return true
+}
+
+// joinBlocked explains why userID can't join the guild's queues right now
+// ("" = they can). Used as-is by join paths that aren't interactions
+// (lobby, fromvoice).
+func joinBlocked(guildID, userID string, now time.Time) string {
+	if ban, ok := bans.Active(guildID, userID, now); ok {
+		return "🚫 You're banned from the queue" + banSuffix(ban)
+	}
+	if left := strikes.Cooldown(guildID, userID, now); left > 0 {
+		n := len(strikes.Active(guildID, userID, now))
+		return fmt.Sprintf("⏳ You're on cooldown (%d active strike(s)). Try again in **%s**.", n, ui.ShortDuration(left))
+	}
+	return ""
+}
+
+// noteLeave records a voluntary leave; the returned text (possibly empty)
+// is appended to the leave reply when it earned a strike.
+func noteLeave(guildID, userID string) string {
+	struck, cd, err := strikes.RecordLeave(guildID, userID, time.Now())
+	if err != nil {
+		log.Printf("[strikes] save error: %v", err)
+	}
+	if !struck {
+		return ""
+	}
+	log.Printf("[strikes] leave-spam strike for %s (cooldown %s)", userID, cd)
+	return fmt.Sprintf("\n⚠️ Too many leaves in a short time: strike issued, join cooldown **%s**.", ui.ShortDuration(cd))
+}
+
+// handleStrikes serves /strikes view|add|clear (admin only).
+func handleStrikes(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !authorize(s, i, perms.ActionBan) {
+		return
+	}
+	sub, opts := subcommand(i)
+	target := opts.user(i, "user")
+	if target == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Pick a user.")
+		return
+	}
+	now := time.Now()
+
+	switch sub {
+	case "view":
+		active := strikes.Active(i.GuildID, target.ID, now)
+		if len(active) == 0 {
+			_ = d.SendEphemeral(s, i, fmt.Sprintf("<@%s> has no active strikes.", target.ID))
+			return
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "<@%s> — %d active strike(s)", target.ID, len(active))
+		if left := strikes.Cooldown(i.GuildID, target.ID, now); left > 0 {
+			fmt.Fprintf(&b, ", cooldown **%s**", ui.ShortDuration(left))
+		}
+		b.WriteString("\n")
+		for _, st := range active {
+			fmt.Fprintf(&b, "• %s <t:%d:R>", st.Reason, st.At.Unix())
+			if st.Ref != "" {
+				fmt.Fprintf(&b, " (%s)", st.Ref)
+			}
+			b.WriteString("\n")
+		}
+		_ = d.SendEphemeral(s, i, b.String())
+
+	case "add":
+		reason := moderation.Reason(opts.str("reason"))
+		if reason == "" {
+			reason = moderation.ReasonManual
+		}
+		actor := d.UserOf(i)
+		cd, err := strikes.Add(i.GuildID, target.ID, reason, "by "+d.SafeName(actor), now)
+		if err != nil {
+			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+			return
+		}
+		log.Printf("[strikes] %s added %s strike to %s", d.SafeName(actor), reason, target.ID)
+		recordAudit(s, i, audit.Entry{Action: audit.ActionStrikeAdd, TargetID: target.ID, TargetName: target.Username, Detail: string(reason)})
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ Strike added to <@%s>; cooldown **%s**.", target.ID, ui.ShortDuration(cd)))
+
+	case "clear":
+		n, err := strikes.Clear(i.GuildID, target.ID)
+		if errors.Is(err, moderation.ErrNoStrikes) {
+			_ = d.SendEphemeral(s, i, fmt.Sprintf("<@%s> has no strikes.", target.ID))
+			return
+		}
+		if err != nil {
+			log.Printf("[strikes] save error: %v", err)
+		}
+		log.Printf("[strikes] %s cleared %d strikes of %s", d.SafeName(d.UserOf(i)), n, target.ID)
+		recordAudit(s, i, audit.Entry{Action: audit.ActionStrikeClear, TargetID: target.ID, TargetName: target.Username, Detail: fmt.Sprintf("%d strike(s)", n)})
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("🧽 Cleared %d strike(s) of <@%s>.", n, target.ID))
+
+	default:
+		_ = d.SendEphemeral(s, i, "⚠️ Unknown subcommand.")
+	}
+}
@@ -0,0 +1,125 @@
+// internal/app/notify.go
+package app
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/notify"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+var notifierOnce sync.Once
+
+// StartNotifier watches queue changes and tells opted-in players when they
+// move up into Queue #1 or become next in line. Pops are handled by the
+// QueuePopped subscriber (subscribeNotify).
+func (b *Bot) StartNotifier() {
+	notifierOnce.Do(func() {
+		w, _ := qman.Subscribe()
+		go func() {
+			last := map[string][]*queue.Queue{} // channelID -> previous snapshot
+			for range w.Ready() {
+				for _, c := range w.Take() {
+					notices := notify.Diff(last[c.ChannelID], c.Queues)
+					last[c.ChannelID] = c.Queues
+					if len(notices) > 0 {
+						b.deliver(c.ChannelID, notices, "")
+					}
+				}
+			}
+		}()
+	})
+}
+
+// subscribeNotify tells popped players their match is starting.
+func (b *Bot) subscribeNotify() func() {
+	return events.Subscribe(func(ev events.QueuePopped) {
+		notices := make([]notify.Notice, 0, len(ev.Players))
+		for _, p := range ev.Players {
+			notices = append(notices, notify.Notice{UserID: p.UserID, Kind: notify.KindPopped})
+		}
+		b.deliver(ev.ChannelID, notices, ev.MatchID)
+	})
+}
+
+func noticeText(k notify.Kind, channelID, matchID string) string {
+	switch k {
+	case notify.KindReachedFirst:
+		return fmt.Sprintf("⬆️ You're now in **Queue #1** in <#%s>. Get ready!", channelID)
+	case notify.KindOneAway:
+		return fmt.Sprintf("⏭️ You're next in line for Queue #1 in <#%s>.", channelID)
+	case notify.KindPopped:
+		msg := fmt.Sprintf("🎮 You've been called for a match from <#%s>!", channelID)
+		if matchID != "" {
+			msg += " https://popflash.site/match/" + matchID
+		}
+		return msg
+	}
+	return ""
+}
+
+// deliver sends notices per each user's preference. Mentions are grouped in
+// one message per kind; a DM that Discord refuses (DMs closed, bot blocked)
+// falls back to a mention so the player still hears about it.
+func (b *Bot) deliver(channelID string, notices []notify.Notice, matchID string) {
+	if b.Sess == nil {
+		return
+	}
+	mentions := map[notify.Kind][]string{}
+	for _, n := range notices {
+		switch notifyPrefs.Get(n.UserID) {
+		case notify.ModeDM:
+			err := d.SendDM(b.Sess, n.UserID, noticeText(n.Kind, channelID, matchID))
+			if err == nil {
+				continue
+			}
+			if !d.IsDMBlocked(err) {
+				log.Printf("[notify] dm %s: %v", n.UserID, err)
+			}
+			mentions[n.Kind] = append(mentions[n.Kind], n.UserID)
+		case notify.ModeMention:
+			mentions[n.Kind] = append(mentions[n.Kind], n.UserID)
+		}
+	}
+	for kind, ids := range mentions {
+		if err := d.SendMentions(b.Sess, channelID, ids, noticeText(kind, channelID, matchID)); err != nil {
+			log.Printf("[notify] mention in %s: %v", channelID, err)
+		}
+	}
+}
+
+// handleNotify serves /notify [mode]: shows or sets the caller's preference.
+func handleNotify(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	u := d.UserOf(i)
+	if u == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
+		return
+	}
+	_, opts := subcommand(i)
+	raw := opts.str("mode")
+	if raw == "" {
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("🔔 Your notifications: **%s**.", notifyPrefs.Get(u.ID)))
+		return
+	}
+	m, ok := notify.ParseMode(raw)
+	if !ok {
+		_ = d.SendEphemeral(s, i, "⚠️ Unknown mode.")
+		return
+	}
+	if err := notifyPrefs.Set(u.ID, m); err != nil {
+		log.Printf("[notify] save error: %v", err)
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
+	}
+	msg := fmt.Sprintf("🔔 Notifications set to **%s**.", m)
+	if m == notify.ModeDM {
+		msg += " If your DMs are closed we'll mention you in the queue channel instead."
+	}
+	_ = d.SendEphemeral(s, i, msg)
+}
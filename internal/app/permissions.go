@@ -0,0 +1,74 @@
+// internal/app/permissions.go
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// handlePermissions serves /permissions view|grant|revoke|reset. Viewing is
+// open to whoever can see the command; changes need "configure".
+func handlePermissions(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub, opts := subcommand(i)
+	if sub == "view" {
+		_ = d.SendEphemeralEmbed(s, i, ui.RenderPermissions(permMatrix.Get(i.GuildID)))
+		return
+	}
+	if !authorize(s, i, perms.ActionConfigure) {
+		return
+	}
+
+	action, ok := perms.ParseAction(opts.str("action"))
+	if !ok {
+		_ = d.SendEphemeral(s, i, "⚠️ "+perms.ErrUnknownAction.Error())
+		return
+	}
+	roleID, userID := opts.role("role"), ""
+	if u := opts.user(i, "user"); u != nil {
+		userID = u.ID
+	}
+
+	var err error
+	switch sub {
+	case "grant":
+		err = permMatrix.Grant(i.GuildID, action, roleID, userID)
+	case "revoke":
+		err = permMatrix.Revoke(i.GuildID, action, roleID, userID)
+	case "reset":
+		err = permMatrix.Reset(i.GuildID, action)
+	default:
+		_ = d.SendEphemeral(s, i, "⚠️ Unknown subcommand.")
+		return
+	}
+	if err != nil {
+		log.Printf("[perms] %s %s: %v", sub, action, err)
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
+	}
+
+	detail := permDetail(sub, action, roleID, userID)
+	recordAudit(s, i, audit.Entry{Action: audit.ActionPermissions, TargetID: userID, Detail: detail})
+	_ = d.SendEphemeralEmbed(s, i, ui.RenderPermissions(permMatrix.Get(i.GuildID)))
+}
+
+func permDetail(sub string, action perms.Action, roleID, userID string) string {
+	var who []string
+	if roleID != "" {
+		who = append(who, "<@&"+roleID+">")
+	}
+	if userID != "" {
+		who = append(who, "<@"+userID+">")
+	}
+	if len(who) == 0 {
+		return fmt.Sprintf("%s %s", sub, action)
+	}
+	return fmt.Sprintf("%s %s: %s", sub, action, strings.Join(who, " "))
+}
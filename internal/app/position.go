@@ -0,0 +1,49 @@
+// internal/app/position.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// etasFor returns the channel's per-queue wait estimates (nil without history).
+func etasFor(channelID string) []time.Duration {
+	etas, ok := qman.ETAs(channelID, time.Now())
+	if !ok {
+		return nil
+	}
+	return etas
+}
+
+// handlePosition serves /position: the caller's queue, slot and estimated wait.
+func handlePosition(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	u := d.UserOf(i)
+	if u == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
+		return
+	}
+	qi, slot, err := qman.Position(channelID, u.ID)
+	switch {
+	case errors.Is(err, queue.ErrNotIn), errors.Is(err, queue.ErrNotFound):
+		_ = d.SendEphemeral(s, i, "You're not in any queue.")
+		return
+	case err != nil:
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
+	}
+
+	msg := fmt.Sprintf("📍 Queue #%d, position %d.", qi+1, slot+1)
+	if etas := etasFor(channelID); qi < len(etas) {
+		msg += " Estimated wait: **" + ui.ETALabel(etas[qi]) + "**."
+	} else {
+		msg += " No estimate yet, not enough matches played."
+	}
+	_ = d.SendEphemeral(s, i, msg)
+}
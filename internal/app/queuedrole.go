@@ -0,0 +1,148 @@
+// internal/app/queuedrole.go
+package app
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+)
+
+var queuedRoleOnce sync.Once
+
+// queuedRoleTick is how often the reconciler looks for guilds whose own
+// QueuedRoleReconcile has elapsed.
+const queuedRoleTick = time.Minute
+
+// roleHolders is who we believe holds a guild's queued role.
+type roleHolders struct {
+	role string
+	ids  map[string]bool
+}
+
+// StartQueuedRole keeps each guild's queued role on exactly the members
+// sitting in that guild's queue: added on join, removed on leave/kick/pop
+// (all seen as queue changes). A reconciler compares against the guilds'
+// actual role holders shortly after start and then every guild's
+// QueuedRoleReconcile to fix drift from restarts or manual role edits.
+// Guilds without a queued role are skipped.
+func (b *Bot) StartQueuedRole() {
+	queuedRoleOnce.Do(func() {
+		w, _ := qman.Subscribe()
+		go func() {
+			held := map[string]*roleHolders{} // guildID -> holders
+			last := map[string]time.Time{}    // guildID -> last reconcile
+			first := time.After(30 * time.Second)
+			t := time.NewTicker(queuedRoleTick)
+			defer t.Stop()
+			for {
+				select {
+				case <-w.Ready():
+					for _, c := range w.Take() {
+						if g, ok := guildOfQueue(c.ChannelID); ok {
+							if h := holdersOf(held, g); h != nil {
+								b.syncQueuedRole(g, h)
+							}
+						}
+					}
+				case <-first:
+					b.reconcileQueuedRoles(held, last, time.Now())
+				case now := <-t.C:
+					b.reconcileQueuedRoles(held, last, now)
+				}
+			}
+		}()
+		log.Printf("[queuedrole] started")
+	})
+}
+
+// holdersOf returns the guild's holders for its current role (nil = no
+// role). A role changed by a reload starts from scratch.
+func holdersOf(held map[string]*roleHolders, guildID string) *roleHolders {
+	role := confOf(guildID).QueuedRoleID
+	if role == "" {
+		delete(held, guildID)
+		return nil
+	}
+	h, ok := held[guildID]
+	if !ok || h.role != role {
+		h = &roleHolders{role: role, ids: map[string]bool{}}
+		held[guildID] = h
+	}
+	return h
+}
+
+// queuedMembers is who should hold the guild's role right now. Mock
+// players have no Discord account, so only snowflake IDs count.
+func queuedMembers(guildID string) map[string]bool {
+	want := map[string]bool{}
+	qs, _ := qman.Queues(queueChannelOf(guildID))
+	for _, q := range qs {
+		for _, p := range q.Players {
+			if _, err := strconv.ParseUint(p.ID, 10, 64); err == nil {
+				want[p.ID] = true
+			}
+		}
+	}
+	return want
+}
+
+// syncQueuedRole applies the difference between h and the guild's queue.
+func (b *Bot) syncQueuedRole(guildID string, h *roleHolders) {
+	want := queuedMembers(guildID)
+	for uid := range want {
+		if h.ids[uid] {
+			continue
+		}
+		if err := d.AddRole(b.Sess, guildID, uid, h.role); err != nil {
+			if !d.IsUnknownMember(err) {
+				log.Printf("[queuedrole] %s add %s: %v", guildID, uid, err)
+			}
+			continue
+		}
+		h.ids[uid] = true
+	}
+	for uid := range h.ids {
+		if want[uid] {
+			continue
+		}
+		if err := d.RemoveRole(b.Sess, guildID, uid, h.role); err != nil && !d.IsUnknownMember(err) {
+			log.Printf("[queuedrole] %s remove %s: %v", guildID, uid, err)
+			continue
+		}
+		delete(h.ids, uid)
+	}
+}
+
+// reconcileQueuedRoles reloads the real role holders of every guild with a
+// queued role whose QueuedRoleReconcile has elapsed since its last run,
+// then syncs. If a member list can't be read (no members intent) it
+// re-adds the role to everyone queued and trusts the holders we know of for
+// removals.
+func (b *Bot) reconcileQueuedRoles(held map[string]*roleHolders, last map[string]time.Time, now time.Time) {
+	for _, g := range b.knownGuilds() {
+		h := holdersOf(held, g)
+		if h == nil {
+			continue
+		}
+		if now.Sub(last[g]) < confOf(g).QueuedRoleReconcile {
+			continue
+		}
+		last[g] = now
+		ids, err := d.MembersWithRole(b.Sess, g, h.role)
+		if err != nil {
+			log.Printf("[queuedrole] %s list holders: %v (partial reconcile)", g, err)
+			for uid := range queuedMembers(g) {
+				delete(h.ids, uid)
+			}
+		} else {
+			clear(h.ids)
+			for _, uid := range ids {
+				h.ids[uid] = true
+			}
+		}
+		b.syncQueuedRole(g, h)
+	}
+}
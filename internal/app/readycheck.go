@@ -0,0 +1,164 @@
+// internal/app/readycheck.go
+package app
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/moderation"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// readyCheckTimeout is how long popped players have to confirm.
+const readyCheckTimeout = 90 * time.Second
+
+// readyCheck is one pop waiting for its players to confirm.
+type readyCheck struct {
+	s                           *discordgo.Session
+	guildID, channelID, matchID string
+	msgID                       string
+	confirmed                   map[string]bool // popped userID -> pressed Ready
+}
+
+var (
+	readyMu     sync.Mutex
+	readyChecks = map[string]*readyCheck{} // check id -> check
+)
+
+// startReadyCheck asks the players popped for a match to press Ready in
+// the queue channel. Whoever hasn't confirmed after readyCheckTimeout gets
+// a ready-check strike. Mock players have no Discord account and are not
+// asked.
+func startReadyCheck(s *discordgo.Session, guildID, channelID, matchID string, popped []queue.Player) {
+	rc := &readyCheck{s: s, guildID: guildID, channelID: channelID, matchID: matchID, confirmed: map[string]bool{}}
+	var ids []string
+	for _, p := range popped {
+		if _, err := strconv.ParseUint(p.ID, 10, 64); err == nil {
+			rc.confirmed[p.ID] = false
+			ids = append(ids, p.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	checkID := strconv.FormatInt(time.Now().UnixNano(), 36)
+	deadline := time.Now().Add(readyCheckTimeout)
+	msg, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("🔔 Match called! %s press **Ready** <t:%d:R> or get a strike.",
+			mentions(ids), deadline.Unix()),
+		Components:      ui.ReadyCheckComponents(checkID),
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: ids},
+	})
+	if err != nil {
+		log.Printf("[ready] post in %s: %v (no ready check)", channelID, err)
+		return
+	}
+	rc.msgID = msg.ID
+
+	readyMu.Lock()
+	readyChecks[checkID] = rc
+	readyMu.Unlock()
+	time.AfterFunc(readyCheckTimeout, func() { finishReadyCheck(checkID) })
+	log.Printf("[ready] check %s for %d player(s) in %s (match=%s)", checkID, len(ids), channelID, matchID)
+}
+
+// handleReadyButton records a Ready press ("ready:<checkID>"). The check
+// ends early once everyone confirmed.
+func handleReadyButton(s *discordgo.Session, i *discordgo.InteractionCreate, checkID string) {
+	u := d.UserOf(i)
+	if u == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
+		return
+	}
+	readyMu.Lock()
+	rc, ok := readyChecks[checkID]
+	var done, was, called bool
+	if ok {
+		was, called = rc.confirmed[u.ID]
+		if called {
+			rc.confirmed[u.ID] = true
+			done = allConfirmed(rc)
+		}
+	}
+	readyMu.Unlock()
+
+	switch {
+	case !ok:
+		_ = d.SendEphemeral(s, i, "⌛ This ready check is over.")
+		return
+	case !called:
+		_ = d.SendEphemeral(s, i, "This ready check isn't for you.")
+		return
+	case was:
+		_ = d.SendEphemeral(s, i, "✅ Already confirmed.")
+		return
+	}
+	_ = d.SendEphemeral(s, i, "✅ Ready! Good luck.")
+	if done {
+		finishReadyCheck(checkID)
+	}
+}
+
+func allConfirmed(rc *readyCheck) bool {
+	for _, ok := range rc.confirmed {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// finishReadyCheck closes a check (at most once): strikes whoever didn't
+// confirm and replaces the button with the outcome.
+func finishReadyCheck(checkID string) {
+	readyMu.Lock()
+	rc, ok := readyChecks[checkID]
+	delete(readyChecks, checkID)
+	var missing []string
+	if ok {
+		for uid, confirmed := range rc.confirmed {
+			if !confirmed {
+				missing = append(missing, uid)
+			}
+		}
+	}
+	readyMu.Unlock()
+	if !ok {
+		return
+	}
+
+	ref := "ready check"
+	if rc.matchID != "" {
+		ref = "match #" + rc.matchID
+	}
+	now := time.Now()
+	for _, uid := range missing {
+		cd, err := strikes.Add(rc.guildID, uid, moderation.ReasonReadyCheck, ref, now)
+		if err != nil {
+			log.Printf("[ready] strike save error: %v", err)
+		}
+		log.Printf("[ready] %s missed the ready check (%s, cooldown %s)", uid, ref, cd)
+	}
+
+	content := "✅ Everyone confirmed."
+	if len(missing) > 0 {
+		content = fmt.Sprintf("⏰ Ready check over. Didn't confirm (strike issued): %s", mentions(missing))
+	}
+	empty := []discordgo.MessageComponent{}
+	if _, err := rc.s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:         rc.channelID,
+		ID:              rc.msgID,
+		Content:         &content,
+		Components:      &empty,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	}); err != nil {
+		log.Printf("[ready] close %s: %v", checkID, err)
+	}
+}
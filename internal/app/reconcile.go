@@ -0,0 +1,89 @@
+// internal/app/reconcile.go
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/domain/match"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+// reconcileMatch compares the players popped from Queue #1 with the
+// hydrated PopFlash roster: linked players who didn't show up are flagged
+// (PlayerNoShow), and queued players already playing are removed from the queue.
+// Unlinked players are ignored, we have no way to tell who they are on PopFlash.
+func (b *Bot) reconcileMatch(ev events.MatchStarted, channelID string, popped []queue.Player, players []match.Player) {
+	if len(players) == 0 {
+		return
+	}
+
+	poppedIDs := make([]string, 0, len(popped))
+	for _, p := range popped {
+		poppedIDs = append(poppedIDs, p.ID)
+	}
+	var queuedIDs []string
+	if qs, err := qman.Queues(channelID); err == nil {
+		for _, q := range qs {
+			for _, p := range q.Players {
+				queuedIDs = append(queuedIDs, p.ID)
+			}
+		}
+	}
+
+	r := links.Reconcile(poppedIDs, queuedIDs, players)
+
+	if len(r.InMatch) > 0 {
+		var removed []string
+		err := serialize(channelID, func() error {
+			_, err := qman.Batch(channelID, capacityOf(channelID), func(tx *queue.Tx) error {
+				for _, uid := range r.InMatch {
+					if tx.Leave(uid) == nil {
+						removed = append(removed, uid)
+						log.Printf("[reconcile] match=%s removed %s from queue (already playing)", ev.MatchID, uid)
+					}
+				}
+				return nil
+			})
+			return err
+		})
+		if err != nil {
+			log.Printf("[reconcile] batch error: %v", err)
+		}
+		for _, uid := range removed {
+			publishLeft(ev.GuildID, channelID, uid, events.LeftInMatch)
+		}
+	}
+	for _, uid := range r.NoShows {
+		log.Printf("[reconcile] match=%s no-show %s", ev.MatchID, uid)
+		events.Publish(events.PlayerNoShow{GuildID: ev.GuildID, ChannelID: channelID, UserID: uid, MatchID: ev.MatchID})
+	}
+	if len(r.Unknown) > 0 {
+		log.Printf("[reconcile] match=%s %d popped players not linked", ev.MatchID, len(r.Unknown))
+	}
+
+	if len(r.NoShows) == 0 && len(r.InMatch) == 0 {
+		return
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "🔎 Match **#%s**:", ev.MatchID)
+	if len(r.NoShows) > 0 {
+		fmt.Fprintf(&msg, "\n• No-show: %s", mentions(r.NoShows))
+	}
+	if len(r.InMatch) > 0 {
+		fmt.Fprintf(&msg, "\n• Already playing, removed from queue: %s", mentions(r.InMatch))
+	}
+	if _, err := b.Sess.ChannelMessageSend(channelID, msg.String()); err != nil {
+		log.Printf("[reconcile] notice send error: %v", err)
+	}
+}
+
+func mentions(ids []string) string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, "<@"+id+">")
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,114 @@
+// internal/app/reload.go
+package app
+
+import (
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+	"github.com/jose-valero/popflash-queue-bot/pkg/config"
+)
+
+var reloadOnce sync.Once
+
+// applyConfig pushes the config into the packages that keep their own copy
+// (capacity, voice policy, admin roles, UI text). Per-guild channels,
+// lobby, team voice, LFG and role tuning, and /setup values are layered on
+// top by applyGuild afterwards.
+func (b *Bot) applyConfig() {
+	c := b.Cfg()
+	setBase(c.Capacity, c.GuildID)
+	d.ConfigureAdminRoles(c.AdminRoleIDs)
+	d.ConfigureVoice(d.VoicePolicy{
+		RequireToJoin:   c.Voice.RequireToJoin,
+		CategoryIDs:     c.Voice.AllowedCategoryIDs,
+		CategoryNames:   c.Voice.AllowedCategoryName,
+		ChannelPrefixes: c.Voice.AllowedPrefixes,
+		AFKChannelID:    c.Voice.AFKChannelID,
+		Grace:           c.Voice.Grace,
+		GraceByChannel:  c.Voice.GraceOverrides,
+		LobbyLeaveDelay: c.Voice.LobbyLeaveDelay,
+	})
+	ui.SetText(ui.Text{
+		QueueTitle:  c.UI.QueueTitle,
+		OpenLabel:   c.UI.OpenLabel,
+		ClosedLabel: c.UI.ClosedLabel,
+		JoinLabel:   c.UI.JoinLabel,
+		LeaveLabel:  c.UI.LeaveLabel,
+	})
+}
+
+// reloadSettle lets an editor finish a save (often several events: write,
+// chmod, or write-temp + rename) before the file is read.
+const reloadSettle = 500 * time.Millisecond
+
+// StartConfigWatcher watches the config file and applies edits without a
+// restart. The directory is watched, not the file, so saves that replace
+// the file are seen too. A file that fails validation is logged and
+// ignored; the running config stays. Credentials never change here (see
+// config.Reload).
+func (b *Bot) StartConfigWatcher() {
+	file := b.Cfg().File
+	if file == "" {
+		return
+	}
+	reloadOnce.Do(func() {
+		w, err := fsnotify.NewWatcher()
+		if err == nil {
+			err = w.Add(filepath.Dir(file))
+		}
+		if err != nil {
+			log.Printf("[config] can't watch %s: %v (edits need a restart)", file, err)
+			if w != nil {
+				_ = w.Close()
+			}
+			return
+		}
+		go func() {
+			defer w.Close()
+			var settle <-chan time.Time
+			for {
+				select {
+				case ev, ok := <-w.Events:
+					if !ok {
+						return
+					}
+					if filepath.Base(ev.Name) == filepath.Base(file) && ev.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+						settle = time.After(reloadSettle)
+					}
+				case err, ok := <-w.Errors:
+					if !ok {
+						return
+					}
+					log.Printf("[config] watch %s: %v", file, err)
+				case <-settle:
+					settle = nil
+					next, err := b.Cfg().Reload()
+					if err != nil {
+						log.Printf("[config] reload of %s rejected, keeping current config: %v", file, err)
+						continue
+					}
+					b.reloadConfig(next)
+				}
+			}
+		}()
+		log.Printf("[config] watching %s", file)
+	})
+}
+
+// reloadConfig publishes next; readers holding the old pointer finish with
+// a consistent (old) config.
+func (b *Bot) reloadConfig(next *config.Config) {
+	b.cfg.Store(next)
+	b.applyConfig()
+	for _, g := range b.knownGuilds() {
+		b.applyGuild(g) // /setup still wins over the file
+	}
+	invalidateAll()
+	log.Printf("[config] reloaded %s", next.File)
+}
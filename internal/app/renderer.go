@@ -0,0 +1,124 @@
+// internal/app/renderer.go
+package app
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// renderDelay groups bursts of changes (a batch, several quick joins) into a
+// single edit of the public message.
+const renderDelay = 300 * time.Millisecond
+
+// renderer owns the public queue message of every channel. It listens to
+// queue.Manager changes and to Invalidate calls (open/close, active matches)
+// and re-renders each channel at most once per renderDelay, always from the
+// latest state.
+type renderer struct {
+	b       *Bot
+	mu      sync.Mutex
+	pending map[string]*time.Timer // channelID -> scheduled render
+	known   map[string]struct{}    // channels rendered at least once
+}
+
+var (
+	view       *renderer
+	renderOnce sync.Once
+)
+
+// StartRenderer subscribes to queue changes and keeps the public embed in sync.
+func (b *Bot) StartRenderer() {
+	renderOnce.Do(func() {
+		r := &renderer{b: b, pending: map[string]*time.Timer{}, known: map[string]struct{}{}}
+		w, _ := qman.Subscribe()
+		go func() {
+			for range w.Ready() {
+				for _, c := range w.Take() {
+					r.Invalidate(c.ChannelID)
+				}
+			}
+		}()
+		view = r
+		log.Printf("[render] renderer started")
+	})
+}
+
+// invalidate asks the renderer (if running) to refresh a channel's message.
+func invalidate(channelID string) {
+	if view != nil && channelID != "" {
+		view.Invalidate(channelID)
+	}
+}
+
+// invalidateAll refreshes every channel with a queue message; used when
+// state shared by all channels (active matches) changes.
+func invalidateAll() {
+	if view != nil {
+		view.InvalidateAll()
+	}
+}
+
+// Invalidate schedules a render of channelID on the channel's actor; calls
+// within renderDelay of a pending render are folded into it.
+func (r *renderer) Invalidate(channelID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pending[channelID]; ok {
+		return
+	}
+	r.pending[channelID] = time.AfterFunc(renderDelay, func() {
+		r.mu.Lock()
+		delete(r.pending, channelID)
+		r.mu.Unlock()
+		renderOn(channelID, func() { r.render(channelID) })
+	})
+}
+
+// InvalidateAll schedules a render of every guild's queue channel and of
+// every channel rendered before.
+func (r *renderer) InvalidateAll() {
+	r.mu.Lock()
+	chans := make([]string, 0, len(r.known))
+	for ch := range r.known {
+		chans = append(chans, ch)
+	}
+	r.mu.Unlock()
+	chans = append(chans, queueChannels()...)
+	for _, ch := range chans {
+		r.Invalidate(ch)
+	}
+}
+
+// render hands the current state to the edit scheduler. Channels the
+// manager never had queues in are skipped, so InvalidateAll can't post in
+// them. A channel whose queues were all closed gets Queue #1 back and is
+// rendered EMPTY (0/N) instead of the "No queues" embed.
+func (r *renderer) render(channelID string) {
+	if qman.Version(channelID) == 0 {
+		return
+	}
+	r.mu.Lock()
+	r.known[channelID] = struct{}{}
+	r.mu.Unlock()
+
+	qs, err := qman.Queues(channelID)
+	if errors.Is(err, queue.ErrNotFound) {
+		if q, e2 := qman.EnsureFirstQueue(channelID, "Queue #1", capacityOf(channelID)); e2 == nil && q != nil {
+			qs, err = []*queue.Queue{q}, nil
+		}
+	}
+	if err != nil {
+		qs = nil
+	}
+
+	open := IsQueueOpen(channelID)
+	emb := ui.RenderQueuesEmbedWithETA(qs, open, cardsOrNil(r.b, guildOfChannel(r.b.Sess, channelID)), etasFor(channelID))
+	comps := ui.ComponentsForQueues(qs, open)
+	d.ScheduleQueueMessage(r.b.Sess, channelID, emb, comps)
+}
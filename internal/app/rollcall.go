@@ -0,0 +1,115 @@
+// internal/app/rollcall.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// handleFromVoice serves /queue fromvoice <channel>: everyone in the voice
+// channel joins the queue in one undoable batch. Bans and cooldowns are
+// respected and the channel itself must pass the voice policy.
+func handleFromVoice(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	if !authorize(s, i, perms.ActionKick) {
+		return
+	}
+	_, opts := subcommand(i)
+	vch := opts.channel("channel")
+	if !voiceOK(s, i.GuildID, vch) {
+		_ = d.SendEphemeral(s, i, "🔇 That voice channel isn't allowed for the queue.")
+		return
+	}
+	users := d.VoiceMembers(s, i.GuildID, vch)
+	if len(users) == 0 {
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("Nobody is in <#%s>.", vch))
+		return
+	}
+
+	now := time.Now()
+	var blocked, already, joined []string
+	var res queue.BatchResult
+	err := audited(s, i, channelID, audit.Entry{Action: audit.ActionFromVoice, Detail: "<#" + vch + ">"}, func() (err error) {
+		blocked, already, joined = nil, nil, nil
+		res, err = qman.Batch(channelID, capacityOf(channelID), func(tx *queue.Tx) error {
+			tx.Undoable("fromvoice")
+			for _, u := range users {
+				if joinBlocked(i.GuildID, u.ID, now) != "" {
+					blocked = append(blocked, u.ID)
+					continue
+				}
+				if err := tx.Join(u.ID, u.Username); errors.Is(err, queue.ErrAlreadyIn) {
+					already = append(already, u.ID)
+					continue
+				}
+				joined = append(joined, u.ID)
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
+	}
+	for _, uid := range joined {
+		events.Publish(events.QueueJoined{GuildID: i.GuildID, ChannelID: channelID, UserID: uid, At: now.UTC()})
+	}
+
+	msg := fmt.Sprintf("🎙️ Enqueued **%d** from <#%s>.", len(joined), vch)
+	if len(already) > 0 {
+		msg += fmt.Sprintf("\nAlready queued: %s", mentions(already))
+	}
+	if len(blocked) > 0 {
+		msg += fmt.Sprintf("\nSkipped (banned/cooldown): %s", mentions(blocked))
+	}
+	if res.Checkpoint != 0 {
+		_ = d.SendEphemeralWithComponents(s, i, msg, ui.UndoComponents(res.Checkpoint))
+		return
+	}
+	_ = d.SendEphemeral(s, i, msg)
+}
+
+// handleRollcall serves /rollcall: queued players who aren't in an allowed
+// voice channel right now (AFK counts as absent).
+func handleRollcall(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string) {
+	if !authorize(s, i, perms.ActionKick) {
+		return
+	}
+	qs, _ := qman.Queues(channelID)
+	var lines []string
+	total := 0
+	for k, q := range qs {
+		for _, p := range q.Players {
+			total++
+			vch, _ := d.VoiceChannelOf(s, i.GuildID, p.ID)
+			if voiceOK(s, i.GuildID, vch) {
+				continue
+			}
+			where := "not in voice"
+			if vch != "" {
+				where = fmt.Sprintf("in <#%s>", vch)
+			}
+			lines = append(lines, fmt.Sprintf("Q#%d · <@%s> — %s", k+1, p.ID, where))
+		}
+	}
+	switch {
+	case total == 0:
+		_ = d.SendEphemeral(s, i, "⚠️ No one is queued.")
+	case len(lines) == 0:
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ All %d queued players are in voice.", total))
+	default:
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("📋 **%d/%d** queued players missing from voice:\n%s",
+			len(lines), total, strings.Join(lines, "\n")))
+	}
+}
@@ -13,15 +13,16 @@ import (
 	"github.com/bwmarrin/discordgo"
 
 	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
 	"github.com/jose-valero/popflash-queue-bot/internal/queue"
 	"github.com/jose-valero/popflash-queue-bot/internal/ui"
 )
 
-var (
-	qman            = queue.NewManager()
-	targetChannelID string
-	defaultCapacity = 5
-)
+// qman holds every guild's queues, keyed by channel; per-guild settings
+// live in guilds.go.
+var qman = queue.NewManager()
 
 // channel open/close flag for joins
 var queueOpen sync.Map        // channelID -> bool
@@ -47,6 +48,8 @@ func SetQueueOpen(channelID string, open bool) {
 		return
 	}
 	queueOpen.Store(channelID, open)
+	invalidate(channelID)
+	lfgRecheck(channelID)
 }
 
 func IsQueueOpen(channelID string) bool {
@@ -58,13 +61,6 @@ func IsQueueOpen(channelID string) bool {
 	return false
 }
 
-func SetRuntimeConfig(channelID string, capacity int) {
-	targetChannelID = channelID
-	if capacity > 0 {
-		defaultCapacity = capacity
-	}
-}
-
 func HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if alreadyHandled(i) {
 		return
@@ -77,10 +73,52 @@ func HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 }
 
+// ------------------- Authorization -------------------
+
+const deniedMsg = "⛔ You don't have permission for this action."
+
+// can checks the caller against the guild's permission matrix; actions
+// without grants keep the old rule (Administrator or ADMIN_ROLE_IDS).
+func can(i *discordgo.InteractionCreate, action perms.Action) bool {
+	mem := perms.Member{Admin: d.IsAdministrator(i), Legacy: d.IsPrivileged(i)}
+	if u := d.UserOf(i); u != nil {
+		mem.UserID = u.ID
+	}
+	if i.Member != nil {
+		mem.Roles = i.Member.Roles
+	}
+	return permMatrix.Allowed(i.GuildID, action, mem)
+}
+
+// authorize is the single gate for admin actions: it replies ephemeral and
+// returns false when the caller may not run action.
+func authorize(s *discordgo.Session, i *discordgo.InteractionCreate, action perms.Action) bool {
+	if can(i, action) {
+		return true
+	}
+	log.Printf("[perms] denied %s to %s", action, d.SafeName(d.UserOf(i)))
+	_ = d.SendEphemeral(s, i, deniedMsg)
+	return false
+}
+
+// canManage: may use the admin panel (any of its actions).
+func canManage(i *discordgo.InteractionCreate) bool {
+	return can(i, perms.ActionReset) || can(i, perms.ActionDelete) || can(i, perms.ActionKick)
+}
+
 // ------------------- Slash -------------------
 
+// mockIDPrefix marks the IDs of players added by /seedqueue; /clearmocks
+// removes exactly those.
+const mockIDPrefix = "mock:"
+
 func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if targetChannelID != "" && i.ChannelID != targetChannelID {
+	// /setup works anywhere: it's how the queue channel gets chosen
+	if i.ApplicationCommandData().Name == "setup" {
+		handleSetup(s, i)
+		return
+	}
+	if qc := queueChannelOf(i.GuildID); qc != "" && i.ChannelID != qc {
 		_ = d.SendEphemeral(s, i, "Use this command in the designated queue channel.")
 		return
 	}
@@ -92,18 +130,23 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch name {
 
 	case "startqueue":
-		if !d.IsPrivileged(i) {
-			_ = d.SendEphemeral(s, i, "Solo admins pueden abrir la cola.")
+		if !authorize(s, i, perms.ActionOpen) {
 			return
 		}
-		if _, err := qman.EnsureFirstQueue(queueID, "Queue #1", defaultCapacity); err != nil {
+		// abrir ANTES de renderizar para que salga habilitado el boton
+		if err := audited(s, i, queueID, audit.Entry{Action: audit.ActionOpen}, func() error {
+			if _, err := qman.EnsureFirstQueue(queueID, "Queue #1", capacityOf(queueID)); err != nil {
+				return err
+			}
+			SetQueueOpen(queueID, true)
+			return nil
+		}); err != nil {
 			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
 			return
 		}
-		// abrir ANTES de renderizar para que salga habilitado el boton
-		SetQueueOpen(queueID, true)
 
-		// 1) Responder EFÍMERO para cumplir el ACK en <3s (no crea mensaje público)
+		// Responder EFÍMERO para cumplir el ACK en <3s; el mensaje PÚBLICO lo
+		// publica/edita el renderer (SetQueueOpen lo invalida)
 		if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -112,17 +155,6 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			},
 		}); err != nil {
 			log.Printf("respond error: %v", err)
-			return
-		}
-
-		// 2) Publicar/editar el mensaje PÚBLICO con nuestra UI y RECORDAR SU ID
-		qs, _ := qman.Queues(queueID)
-		if err := d.PublishOrEditQueueMessage(
-			s, queueID,
-			ui.RenderQueuesEmbed(qs, IsQueueOpen(queueID), ActiveList()),
-			ui.ComponentsForQueues(qs, IsQueueOpen(queueID)),
-		); err != nil {
-			log.Printf("PublishOrEditQueueMessage error: %v", err)
 		}
 		return
 
@@ -141,7 +173,13 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			_ = d.SendEphemeral(s, i, "🔇 You must be in an allowed voice channel to join.")
 			return
 		}
-		if _, err := qman.JoinAny(queueID, u.ID, u.Username, defaultCapacity); err != nil {
+		if !joinAllowed(s, i, u.ID) {
+			return
+		}
+		if err := serialize(queueID, func() error {
+			_, err := qman.JoinAny(queueID, u.ID, u.Username, capacityOf(queueID))
+			return err
+		}); err != nil {
 			if errors.Is(err, queue.ErrAlreadyIn) {
 				_ = d.SendEphemeral(s, i, "You're already in a queue.")
 				return
@@ -150,7 +188,7 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			return
 		}
 		_ = d.SendEphemeral(s, i, "🙌 Done! Added you to the first queue with space.")
-		updateUIAfterChange(s, i, queueID)
+		events.Publish(events.QueueJoined{GuildID: i.GuildID, ChannelID: queueID, UserID: u.ID, At: time.Now().UTC()})
 		return
 
 	case "leavequeue":
@@ -159,7 +197,10 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
 			return
 		}
-		if _, err := qman.LeaveAny(queueID, u.ID); err != nil {
+		if err := serialize(queueID, func() error {
+			_, err := qman.LeaveAny(queueID, u.ID)
+			return err
+		}); err != nil {
 			switch {
 			case errors.Is(err, queue.ErrNotIn):
 				_ = d.SendEphemeral(s, i, "⚠️ You're not in any queue.")
@@ -168,26 +209,41 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			}
 			return
 		}
-		_ = d.SendEphemeral(s, i, "👋 Left your queue and re-balanced lists.")
-		updateUIAfterChange(s, i, queueID)
+		_ = d.SendEphemeral(s, i, "👋 Left your queue and re-balanced lists."+noteLeave(i.GuildID, u.ID))
+		publishLeft(i.GuildID, queueID, u.ID, events.LeftVoluntary)
+		return
+
+	case "notify":
+		handleNotify(s, i)
+		return
+
+	case "position":
+		handlePosition(s, i, queueID)
 		return
 
 	case "queue":
-		if qs, err := qman.Queues(queueID); err == nil {
-			if d.IsPrivileged(i) {
+		switch sub, _ := subcommand(i); sub {
+		case "undo":
+			handleUndo(s, i, queueID, 0)
+			return
+		case "fromvoice":
+			handleFromVoice(s, i, queueID)
+			return
+		}
+		if qs, v, err := qman.Snapshot(queueID); err == nil {
+			if canManage(i) {
 				// Admin: embed + selects solo para él (efímero)
-				_ = d.SendEphemeralComplex(s, i, ui.RenderQueuesEmbed(qs, IsQueueOpen(queueID), ActiveList()), ui.AdminComponentsForQueues(qs))
+				_ = d.SendEphemeralComplex(s, i, ui.RenderQueuesEmbedWithETA(qs, IsQueueOpen(queueID), ActiveList(i.GuildID), etasFor(queueID)), ui.AdminComponentsForQueues(qs, v))
 			} else {
 				// No admin: solo embed efímero (sin selects)
-				_ = d.SendEphemeralEmbed(s, i, ui.RenderQueuesEmbed(qs, IsQueueOpen(queueID), ActiveList()))
+				_ = d.SendEphemeralEmbed(s, i, ui.RenderQueuesEmbedWithETA(qs, IsQueueOpen(queueID), ActiveList(i.GuildID), etasFor(queueID)))
 			}
 		} else {
 			_ = d.SendEphemeral(s, i, "⚠️ No active queues.")
 		}
 		return
 	case "seedqueue":
-		if !d.IsPrivileged(i) {
-			_ = d.SendEphemeral(s, i, "Solo admins.")
+		if !authorize(s, i, perms.ActionSeed) {
 			return
 		}
 		// defaults
@@ -209,44 +265,102 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		}
 
 		// Asegura que exista la Q#1
-		if _, err := qman.EnsureFirstQueue(queueID, "Queue #1", defaultCapacity); err != nil {
+		if _, err := qman.EnsureFirstQueue(queueID, "Queue #1", capacityOf(queueID)); err != nil {
 			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
 			return
 		}
 
-		// Agrega N jugadores mock distribuidos con JoinAny
-		now := time.Now().UnixNano()
-		for k := 0; k < n; k++ {
-			// IDs únicos y fáciles de limpiar luego
-			uid := fmt.Sprintf("%s:%d:%d", prefix, now, k)
-			uname := fmt.Sprintf("%s-%02d", prefix, k+1)
-			_, _ = qman.JoinAny(queueID, uid, uname, defaultCapacity)
+		// Agrega N jugadores mock en un solo batch (un rebalance, un solo cambio)
+		var res queue.BatchResult
+		if err := audited(s, i, queueID, audit.Entry{Action: audit.ActionSeed, Detail: fmt.Sprintf("%d × %q", n, prefix)}, func() (err error) {
+			now := time.Now().UnixNano()
+			res, err = qman.Batch(queueID, capacityOf(queueID), func(tx *queue.Tx) error {
+				for k := 0; k < n; k++ {
+					// IDs únicos bajo mockIDPrefix (sea cual sea el prefijo
+					// del nombre), así clearmocks los encuentra todos
+					uid := fmt.Sprintf("%s%s:%d:%d", mockIDPrefix, prefix, now, k)
+					uname := fmt.Sprintf("%s-%02d", prefix, k+1)
+					_ = tx.Join(uid, uname)
+				}
+				return nil
+			})
+			return err
+		}); err != nil {
+			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+			return
 		}
 
-		_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ Se agregaron %d jugadores %q.", n, prefix))
-		updateUIAfterChange(s, i, queueID)
+		_ = d.SendEphemeral(s, i, fmt.Sprintf("✅ Se agregaron %d jugadores %q.", res.Joined, prefix))
 		return
 
 	case "clearmocks":
-		if !d.IsPrivileged(i) {
-			_ = d.SendEphemeral(s, i, "Solo admins.")
-			return
-		}
-		// Remueve cualquier jugador cuyo ID empiece con "mock:" (o el prefijo que uses)
-		removed := 0
-		if qs, err := qman.Queues(queueID); err == nil {
-			for _, q := range qs {
-				for _, p := range q.Players {
-					if strings.HasPrefix(p.ID, "mock:") || strings.HasPrefix(p.ID, "mock") {
-						if _, err := qman.LeaveAny(queueID, p.ID); err == nil {
-							removed++
-						}
+		if !authorize(s, i, perms.ActionSeed) {
+			return
+		}
+		// Remueve todo jugador sembrado por seedqueue (ID con mockIDPrefix),
+		// todo en un batch atómico y deshacible
+		var res queue.BatchResult
+		if err := audited(s, i, queueID, audit.Entry{Action: audit.ActionClearMocks}, func() (err error) {
+			res, err = qman.Batch(queueID, capacityOf(queueID), func(tx *queue.Tx) error {
+				tx.Undoable("clearmocks")
+				for _, p := range tx.Players() {
+					if strings.HasPrefix(p.ID, mockIDPrefix) {
+						_ = tx.Leave(p.ID)
 					}
 				}
-			}
+				return nil
+			})
+			return err
+		}); err != nil {
+			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+			return
+		}
+		msg := fmt.Sprintf("🧹 Quitados %d jugadores mock.", res.Left)
+		if res.Checkpoint != 0 {
+			_ = d.SendEphemeralWithComponents(s, i, msg, ui.UndoComponents(res.Checkpoint))
+		} else {
+			_ = d.SendEphemeral(s, i, msg)
 		}
-		_ = d.SendEphemeral(s, i, fmt.Sprintf("🧹 Quitados %d jugadores mock.", removed))
-		updateUIAfterChange(s, i, queueID)
+		return
+
+	case "link":
+		handleLink(s, i)
+		return
+
+	case "unlink":
+		handleUnlink(s, i)
+		return
+
+	case "strikes":
+		handleStrikes(s, i)
+		return
+
+	case "queueban":
+		handleQueueBan(s, i, queueID)
+		return
+
+	case "queueunban":
+		handleQueueUnban(s, i)
+		return
+
+	case "queuebans":
+		handleQueueBans(s, i)
+		return
+
+	case "queuestats":
+		handleQueueStats(s, i)
+		return
+
+	case "auditlog":
+		handleAuditLog(s, i)
+		return
+
+	case "rollcall":
+		handleRollcall(s, i, queueID)
+		return
+
+	case "permissions":
+		handlePermissions(s, i)
 		return
 	}
 }
@@ -254,22 +368,46 @@ func handleSlash(s *discordgo.Session, i *discordgo.InteractionCreate) {
 // ------------------- Components -------------------
 
 func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if targetChannelID != "" && i.ChannelID != targetChannelID {
+	customID := i.MessageComponentData().CustomID
+	u := d.UserOf(i)
+
+	// /setup wizard selects, usable from any channel
+	if strings.HasPrefix(customID, "setup:") {
+		handleSetupSelect(s, i, customID)
+		return
+	}
+
+	// Ready check after a pop: posted in the queue channel, answered by the
+	// popped players only ("ready:<checkID>")
+	if strings.HasPrefix(customID, "ready:") {
+		handleReadyButton(s, i, strings.TrimPrefix(customID, "ready:"))
+		return
+	}
+
+	// Button on an LFG cross-post: lives in another channel and carries the
+	// queue channel it joins ("lfg_join:<channelID>")
+	if strings.HasPrefix(customID, "lfg_join:") {
+		ch := strings.TrimPrefix(customID, "lfg_join:")
+		log.Printf("[component] %s by %s", customID, d.SafeName(u))
+		if qc := queueChannelOf(i.GuildID); ch == "" || (qc != "" && ch != qc) {
+			_ = d.SendEphemeral(s, i, "⚠️ This LFG post is outdated.")
+			return
+		}
+		handleJoinButton(s, i, ch)
+		return
+	}
+
+	if qc := queueChannelOf(i.GuildID); qc != "" && i.ChannelID != qc {
 		_ = d.SendEphemeral(s, i, "Use buttons in the designated queue channel.")
 		return
 	}
 
 	queueID := i.ChannelID
-	customID := i.MessageComponentData().CustomID
-	u := d.UserOf(i)
 	log.Printf("[component] %s by %s", customID, d.SafeName(u))
 
-	// Select: actions per queue ("reset:N" / "close:N")
-
-	if customID == "queue_action" {
-		if !d.RequirePrivileged(s, i) {
-			return
-		}
+	// Select: actions per queue ("reset:<queueID>" / "close:<queueID>"),
+	// CustomID carries the panel version ("queue_action:v<N>")
+	if strings.HasPrefix(customID, "queue_action") {
 		vals := i.MessageComponentData().Values
 		if len(vals) == 0 {
 			_ = d.SendEphemeral(s, i, "⚠️ Invalid selection.")
@@ -280,31 +418,50 @@ func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			_ = d.SendEphemeral(s, i, "⚠️ Invalid selection.")
 			return
 		}
-		idx, _ := strconv.Atoi(parts[1])
+		act, ok := map[string]perms.Action{"reset": perms.ActionReset, "close": perms.ActionDelete}[parts[0]]
+		if !ok {
+			_ = d.SendEphemeral(s, i, "⚠️ Unknown action.")
+			return
+		}
+		if !authorize(s, i, act) {
+			return
+		}
+		qid, version := parts[1], ui.PanelVersion(customID)
+		if version == 0 {
+			refreshAdminPanel(s, i, queueID, "⚠️ This panel is outdated. Here's a fresh one.")
+			return
+		}
 
-		var err error
+		entry := audit.Entry{Detail: queueLabel(queueID, qid)}
+		var seq uint64
+		var mutate func() error
 		switch parts[0] {
 		case "reset":
-			err = qman.ResetAt(queueID, idx)
+			entry.Action = audit.ActionReset
+			mutate = func() (err error) { seq, err = qman.ResetQueue(queueID, qid, version); return err }
 		case "close":
-			err = qman.DeleteAt(queueID, idx)
+			entry.Action = audit.ActionClose
+			mutate = func() (err error) { seq, err = qman.DeleteQueue(queueID, qid, version); return err }
 		default:
 			_ = d.SendEphemeral(s, i, "⚠️ Unknown action.")
 			return
 		}
-		if err != nil {
+		if err := audited(s, i, queueID, entry, mutate); err != nil {
+			if errors.Is(err, queue.ErrStale) {
+				refreshAdminPanel(s, i, queueID, "⚠️ The queue changed since this panel was opened. Nothing was done; here's the current state.")
+				return
+			}
 			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
 			return
 		}
 
-		_ = d.SendEphemeral(s, i, "✅ Done.")
-		updateUIAfterChange(s, i, queueID)
+		_ = d.SendEphemeralWithComponents(s, i, "✅ Done.", ui.UndoComponents(seq))
 		return
 	}
 
-	// Select: kick ("uid:<userID>")
-	if customID == "queue_kick" {
-		if !d.RequirePrivileged(s, i) {
+	// Select: kick ("uid:<userID>"), CustomID "queue_kick:v<N>"
+	if strings.HasPrefix(customID, "queue_kick") {
+		if !authorize(s, i, perms.ActionKick) {
 			return
 		}
 		vals := i.MessageComponentData().Values
@@ -313,9 +470,21 @@ func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			return
 		}
 		uid := strings.TrimPrefix(vals[0], "uid:")
+		version := ui.PanelVersion(customID)
+		if version == 0 {
+			refreshAdminPanel(s, i, queueID, "⚠️ This panel is outdated. Here's a fresh one.")
+			return
+		}
 
-		if _, err := qman.LeaveAny(queueID, uid); err != nil {
+		var seq uint64
+		entry := audit.Entry{Action: audit.ActionKick, TargetID: uid, TargetName: playerName(queueID, uid)}
+		if err := audited(s, i, queueID, entry, func() (err error) {
+			seq, err = qman.Kick(queueID, uid, version)
+			return err
+		}); err != nil {
 			switch {
+			case errors.Is(err, queue.ErrStale):
+				refreshAdminPanel(s, i, queueID, "⚠️ The queue changed since this panel was opened. Nothing was done; here's the current state.")
 			case errors.Is(err, queue.ErrNotIn):
 				_ = d.SendEphemeral(s, i, "⚠️ That user is not in any queue.")
 			case errors.Is(err, queue.ErrNotFound):
@@ -326,34 +495,20 @@ func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			return
 		}
 
-		_ = d.SendEphemeral(s, i, "✅ Player kicked.")
-		updateUIAfterChange(s, i, queueID)
+		_ = d.SendEphemeralWithComponents(s, i, "✅ Player kicked.", ui.UndoComponents(seq))
+		publishLeft(i.GuildID, queueID, uid, events.LeftKicked)
+		return
+	}
+
+	// Button: undo a destructive admin action ("queue_undo:<seq>")
+	if strings.HasPrefix(customID, "queue_undo:") {
+		seq, _ := strconv.ParseUint(strings.TrimPrefix(customID, "queue_undo:"), 10, 64)
+		handleUndo(s, i, queueID, seq)
 		return
 	}
 
 	if strings.HasPrefix(customID, "queue_join") {
-		if u == nil {
-			_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
-			return
-		}
-		if !IsQueueOpen(queueID) {
-			_ = d.SendEphemeral(s, i, "🔒 Queue is closed. Wait for the next **match started**.")
-			return
-		}
-		if d.VoiceRequireToJoin() && !d.IsUserInAllowedVoice(s, i.GuildID, u.ID) {
-			_ = d.SendEphemeral(s, i, "🔇 You must be in an allowed voice channel to join.")
-			return
-		}
-		if _, err := qman.JoinAny(queueID, u.ID, u.Username, defaultCapacity); err != nil {
-			if errors.Is(err, queue.ErrAlreadyIn) {
-				_ = d.SendEphemeral(s, i, "You're already in a queue.")
-				return
-			}
-			_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
-			return
-		}
-		_ = d.SendEphemeral(s, i, "🙌 Joined!")
-		updateUIAfterChange(s, i, queueID)
+		handleJoinButton(s, i, queueID)
 		return
 	}
 
@@ -364,7 +519,10 @@ func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
 			return
 		}
-		if _, err := qman.LeaveAny(queueID, u.ID); err != nil {
+		if err := serialize(queueID, func() error {
+			_, err := qman.LeaveAny(queueID, u.ID)
+			return err
+		}); err != nil {
 			switch {
 			case errors.Is(err, queue.ErrNotIn):
 				_ = d.SendEphemeral(s, i, "⚠️ You're not in any queue.")
@@ -373,20 +531,21 @@ func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			}
 			return
 		}
-		_ = d.SendEphemeral(s, i, "👋 Left.")
-		updateUIAfterChange(s, i, queueID)
+		_ = d.SendEphemeral(s, i, "👋 Left."+noteLeave(i.GuildID, u.ID))
+		publishLeft(i.GuildID, queueID, u.ID, events.LeftVoluntary)
 		return
 
 	case "admin_panel":
-		if !d.RequirePrivileged(s, i) {
+		if !canManage(i) {
+			_ = d.SendEphemeral(s, i, deniedMsg)
 			return
 		}
-		if qs, err := qman.Queues(queueID); err == nil && len(qs) > 0 {
+		if qs, v, err := qman.Snapshot(queueID); err == nil && len(qs) > 0 {
 			// we just render one embed
 			_ = d.SendEphemeralComponents(
 				s,
 				i,
-				ui.AdminComponentsForQueues(qs),
+				ui.AdminComponentsForQueues(qs, v),
 			)
 		} else {
 			_ = d.SendEphemeral(s, i, "⚠️ No active queues.")
@@ -396,36 +555,64 @@ func handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 }
 
-// updateUIAfterChange refreshes the public embed+components OUTSIDE the interaction.
-// If the manager reports no queues (ErrNotFound), we ensure Queue #1 exists and
-// render it EMPTY (0/N) instead of showing the “No queues” embed.
-func updateUIAfterChange(s *discordgo.Session, _ *discordgo.InteractionCreate, channelID string) {
-	var (
-		qs  []*queue.Queue
-		err error
-	)
-
-	qs, err = qman.Queues(channelID)
-	if errors.Is(err, queue.ErrNotFound) {
-		if q, e2 := qman.EnsureFirstQueue(channelID, "Queue #1", defaultCapacity); e2 == nil && q != nil {
-			qs = []*queue.Queue{q}
-			err = nil
+// handleJoinButton joins the clicker to queueID's first queue with space;
+// used by the queue message button and by LFG cross-posts.
+func handleJoinButton(s *discordgo.Session, i *discordgo.InteractionCreate, queueID string) {
+	u := d.UserOf(i)
+	if u == nil {
+		_ = d.SendEphemeral(s, i, "⚠️ Could not identify you.")
+		return
+	}
+	if !IsQueueOpen(queueID) {
+		_ = d.SendEphemeral(s, i, "🔒 Queue is closed. Wait for the next **match started**.")
+		return
+	}
+	if d.VoiceRequireToJoin() && !d.IsUserInAllowedVoice(s, i.GuildID, u.ID) {
+		_ = d.SendEphemeral(s, i, "🔇 You must be in an allowed voice channel to join.")
+		return
+	}
+	if !joinAllowed(s, i, u.ID) {
+		return
+	}
+	if err := serialize(queueID, func() error {
+		_, err := qman.JoinAny(queueID, u.ID, u.Username, capacityOf(queueID))
+		return err
+	}); err != nil {
+		if errors.Is(err, queue.ErrAlreadyIn) {
+			_ = d.SendEphemeral(s, i, "You're already in a queue.")
+			return
 		}
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
 	}
+	msg := "🙌 Joined!"
+	if i.ChannelID != queueID {
+		msg += fmt.Sprintf(" See <#%s>.", queueID)
+	}
+	_ = d.SendEphemeral(s, i, msg)
+	events.Publish(events.QueueJoined{GuildID: i.GuildID, ChannelID: queueID, UserID: u.ID, At: time.Now().UTC()})
+}
 
-	var emb *discordgo.MessageEmbed
-	var comps []discordgo.MessageComponent
-
-	if err == nil && len(qs) > 0 {
-		emb = ui.RenderQueuesEmbed(qs, IsQueueOpen(channelID), ActiveList())
-		comps = ui.ComponentsForQueues(qs, IsQueueOpen(channelID))
-	} else {
-		// Fallback (shouldn’t normally happen after EnsureFirstQueue)
-		emb = ui.RenderQueuesEmbed(nil, IsQueueOpen(channelID), ActiveList())
-		comps = ui.ComponentsForQueues(nil, IsQueueOpen(channelID))
+// refreshAdminPanel re-renders the ephemeral admin panel in place after a
+// stale click, so the admin sees the current queues before acting again.
+func refreshAdminPanel(s *discordgo.Session, i *discordgo.InteractionCreate, channelID, note string) {
+	qs, v, err := qman.Snapshot(channelID)
+	if err != nil || len(qs) == 0 {
+		_ = d.UpdateMessageComplex(s, i, note+"\n_No active queues._", nil, []discordgo.MessageComponent{})
+		return
 	}
+	_ = d.UpdateMessageComplex(s, i, note,
+		ui.RenderQueuesEmbedWithETA(qs, IsQueueOpen(channelID), ActiveList(i.GuildID), etasFor(channelID)),
+		ui.AdminComponentsForQueues(qs, v))
+}
 
-	if err2 := d.EditQueueMessage(s, channelID, emb, comps); err2 != nil {
-		log.Printf("updateUIAfterChange: edit failed: %v", err2)
+// queueLabel describes a queue ID by its current position ("Q#2 (chan:7)").
+func queueLabel(channelID, queueID string) string {
+	qs, _ := qman.Queues(channelID)
+	for k, q := range qs {
+		if q.ID == queueID {
+			return fmt.Sprintf("Q#%d (%s)", k+1, queueID)
+		}
 	}
+	return queueID
 }
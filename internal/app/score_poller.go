@@ -6,70 +6,58 @@ import (
 	"log"
 	"sync"
 	"time"
-
-	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
-	"github.com/jose-valero/popflash-queue-bot/internal/queue"
-	"github.com/jose-valero/popflash-queue-bot/internal/ui"
 )
 
 var pollOnce sync.Once
 
+// StartScorePoller refreshes active match cards every PollSeconds; the
+// interval follows config reloads.
 func (b *Bot) StartScorePoller() {
 	pollOnce.Do(func() {
 		go func() {
-			ticker := time.NewTicker(60 * time.Second)
+			every := b.pollInterval()
+			ticker := time.NewTicker(every)
 			defer ticker.Stop()
 
 			for range ticker.C {
-				// No hay partidas o no hay cliente: nada que hacer.
-				if b == nil || b.PF == nil || ActiveCount() == 0 {
-					continue
-				}
-
-				ids := make([]string, 0, ActiveCount())
-				for _, c := range ActiveList() {
-					ids = append(ids, c.ID)
+				if next := b.pollInterval(); next != every {
+					every = next
+					ticker.Reset(every)
+					log.Printf("[poll] interval now %s", every)
 				}
-				if len(ids) == 0 {
+				// No hay partidas, no hay cliente o el poller quedó apagado: nada que hacer.
+				if b == nil || b.PF == nil || b.Cfg().PollSeconds <= 0 {
 					continue
 				}
 
-				changed := false
-				for _, id := range ids {
-					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-					card, err := b.PF.MatchCard(ctx, id)
-					cancel()
-					if err != nil {
-						log.Printf("[poll] match %s err: %v", id, err)
-						continue
-					}
-					// Guardamos (si no cambió, sobrescribe igual; está OK)
-					ActivePut(card)
-					changed = true
-
-					// micro-pausa entre requests, por cortesía
-					time.Sleep(1200 * time.Millisecond)
-				}
-
-				// Si actualizamos algo, re-renderizamos el embed público
-				if changed {
-					ch := b.Cfg.QueueChannelID
-					qs, err := qman.Queues(ch)
-					if err != nil && err != queue.ErrNotFound {
-						continue
-					}
-					if err == queue.ErrNotFound {
-						if q, e2 := qman.EnsureFirstQueue(ch, "Queue #1", defaultCapacity); e2 == nil && q != nil {
-							qs = []*queue.Queue{q}
+				for _, guildID := range ActiveGuilds() {
+					for _, c := range ActiveList(guildID) {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+						card, err := b.PF.MatchCard(ctx, c.ID)
+						cancel()
+						if err != nil {
+							log.Printf("[poll] match %s err: %v", c.ID, err)
+							continue
 						}
+						// Guardamos (si no cambió, sobrescribe igual; está OK).
+						// ActivePut invalida el embed público, el renderer agrupa los cambios.
+						ActivePut(guildID, card)
+
+						// micro-pausa entre requests, por cortesía
+						time.Sleep(1200 * time.Millisecond)
 					}
-					_ = d.PublishOrEditQueueMessage(
-						b.Sess, ch,
-						ui.RenderQueuesEmbed(qs, IsQueueOpen(ch), ActiveList()),
-						ui.ComponentsForQueues(qs, IsQueueOpen(ch)),
-					)
 				}
 			}
 		}()
 	})
 }
+
+// pollInterval is PollSeconds as a duration; 0 (poller off at runtime)
+// falls back to a slow tick so a reload can bring it back.
+func (b *Bot) pollInterval() time.Duration {
+	n := b.Cfg().PollSeconds
+	if n <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(n) * time.Second
+}
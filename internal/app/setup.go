@@ -0,0 +1,113 @@
+// internal/app/setup.go
+package app
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/settings"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// onSettings applies a guild's saved settings; RegisterHandlers points it at
+// Bot.applyGuild (the router has no Bot).
+var onSettings = func(string, settings.Guild) {}
+
+// handleSetup serves /setup: the wizard is an ephemeral embed with selects,
+// one section at a time.
+func handleSetup(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !authorize(s, i, perms.ActionConfigure) {
+		return
+	}
+	_, opts := subcommand(i)
+	section := opts.str("section")
+	g, _ := guildSettings.Get(i.GuildID)
+	_ = d.SendEphemeralComplex(s, i, ui.RenderSetup(g, section, setupCurrent(i.GuildID)), ui.SetupComponents(g, section))
+}
+
+// setupCurrent is what the guild runs with now, for the wizard.
+func setupCurrent(guildID string) ui.SetupCurrent {
+	gc := confOf(guildID)
+	return ui.SetupCurrent{
+		Capacity:            capacityOf(gc.QueueChannelID),
+		LobbyChannelID:      gc.LobbyChannelID,
+		VoiceGrace:          gc.VoiceGrace,
+		TeamVoice:           gc.TeamVoice,
+		TeamVoiceCategoryID: gc.TeamVoiceCategoryID,
+		LFGMissing:          gc.LFGMissing,
+		LFGCooldown:         gc.LFGCooldown,
+		QueuedRoleReconcile: gc.QueuedRoleReconcile,
+	}
+}
+
+// handleSetupSelect saves one wizard select, applies it and redraws the
+// wizard in place.
+func handleSetupSelect(s *discordgo.Session, i *discordgo.InteractionCreate, customID string) {
+	if !authorize(s, i, perms.ActionConfigure) {
+		return
+	}
+	vals := i.MessageComponentData().Values
+	first := ""
+	if len(vals) > 0 {
+		first = vals[0]
+	}
+
+	g, err := guildSettings.Update(i.GuildID, func(g *settings.Guild) {
+		switch customID {
+		case ui.SetupQueueChannel:
+			if first != "" {
+				g.QueueChannelID = first
+			}
+		case ui.SetupAnnounceChannel:
+			if first != "" {
+				g.AnnounceChannelID = first
+			}
+		case ui.SetupAdminRoles:
+			g.AdminRoleIDs = vals
+		case ui.SetupVoiceCategories:
+			g.VoiceCategoryIDs = vals
+		case ui.SetupCapacity:
+			g.Capacity, _ = strconv.Atoi(first)
+		case ui.SetupLobbyChannel:
+			g.LobbyChannelID = first
+		case ui.SetupTeamCategory:
+			g.TeamVoiceCategoryID = first
+		case ui.SetupTeamVoice:
+			g.TeamVoice = nil
+			if first == "on" || first == "off" {
+				on := first == "on"
+				g.TeamVoice = &on
+			}
+		case ui.SetupVoiceGrace:
+			g.VoiceGraceSeconds, _ = strconv.Atoi(first)
+		case ui.SetupLFGMissing:
+			g.LFGMissing, _ = strconv.Atoi(first)
+		case ui.SetupLFGCooldown:
+			g.LFGCooldownMinutes, _ = strconv.Atoi(first)
+		case ui.SetupRoleReconcile:
+			g.QueuedRoleReconcileMinutes, _ = strconv.Atoi(first)
+		}
+	})
+	if err != nil {
+		log.Printf("[setup] %s: %v", customID, err)
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
+	}
+	onSettings(i.GuildID, g)
+	if customID == ui.SetupCapacity {
+		// existing queues take the new capacity too, not just new ones
+		if ch := queueChannelOf(i.GuildID); ch != "" {
+			if err := serialize(ch, func() error { return qman.SetCapacity(ch, capacityOf(ch)) }); err != nil {
+				log.Printf("[setup] relayout %s: %v", ch, err)
+			}
+		}
+	}
+	recordAudit(s, i, audit.Entry{Action: audit.ActionSetup, Detail: customID})
+	section := ui.SetupSectionOf(customID)
+	_ = d.UpdateMessageComplex(s, i, "", ui.RenderSetup(g, section, setupCurrent(i.GuildID)), ui.SetupComponents(g, section))
+}
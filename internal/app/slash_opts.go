@@ -0,0 +1,80 @@
+// internal/app/slash_opts.go
+package app
+
+import "github.com/bwmarrin/discordgo"
+
+type slashOpts map[string]*discordgo.ApplicationCommandInteractionDataOption
+
+// subcommand returns the invoked subcommand name (if any) and its options
+// indexed by name. For commands without subcommands, name is "".
+func subcommand(i *discordgo.InteractionCreate) (string, slashOpts) {
+	opts := i.ApplicationCommandData().Options
+	if len(opts) == 1 && (opts[0].Type == discordgo.ApplicationCommandOptionSubCommand ||
+		opts[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup) {
+		return opts[0].Name, indexOpts(opts[0].Options)
+	}
+	return "", indexOpts(opts)
+}
+
+func indexOpts(opts []*discordgo.ApplicationCommandInteractionDataOption) slashOpts {
+	m := make(slashOpts, len(opts))
+	for _, o := range opts {
+		m[o.Name] = o
+	}
+	return m
+}
+
+func (o slashOpts) str(name string) string {
+	if v, ok := o[name]; ok && v.Type == discordgo.ApplicationCommandOptionString {
+		return v.StringValue()
+	}
+	return ""
+}
+
+func (o slashOpts) int(name string, def int) int {
+	if v, ok := o[name]; ok && v.Type == discordgo.ApplicationCommandOptionInteger {
+		return int(v.IntValue())
+	}
+	return def
+}
+
+func (o slashOpts) bool(name string) bool {
+	if v, ok := o[name]; ok && v.Type == discordgo.ApplicationCommandOptionBoolean {
+		return v.BoolValue()
+	}
+	return false
+}
+
+// channel returns the ID of a channel option ("" if absent).
+func (o slashOpts) channel(name string) string {
+	if v, ok := o[name]; ok && v.Type == discordgo.ApplicationCommandOptionChannel {
+		id, _ := v.Value.(string)
+		return id
+	}
+	return ""
+}
+
+// role returns the ID of a role option ("" if absent).
+func (o slashOpts) role(name string) string {
+	if v, ok := o[name]; ok && v.Type == discordgo.ApplicationCommandOptionRole {
+		id, _ := v.Value.(string)
+		return id
+	}
+	return ""
+}
+
+// user resolves a user option, preferring the payload's resolved data so we
+// don't hit REST for a username.
+func (o slashOpts) user(i *discordgo.InteractionCreate, name string) *discordgo.User {
+	v, ok := o[name]
+	if !ok || v.Type != discordgo.ApplicationCommandOptionUser {
+		return nil
+	}
+	id, _ := v.Value.(string)
+	if r := i.ApplicationCommandData().Resolved; r != nil {
+		if u, ok := r.Users[id]; ok && u != nil {
+			return u
+		}
+	}
+	return &discordgo.User{ID: id}
+}
@@ -0,0 +1,119 @@
+// internal/app/stats.go
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+	"github.com/jose-valero/popflash-queue-bot/internal/ui"
+)
+
+// statsPeriods maps /queuestats period choices to their length.
+var statsPeriods = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// subscribeStats feeds the stats store from queue events.
+func subscribeStats() []func() {
+	return []func(){
+		events.Subscribe(func(ev events.QueueJoined) {
+			if err := statsStore.Joined(ev.GuildID, ev.At); err != nil {
+				log.Printf("[stats] save error: %v", err)
+			}
+		}),
+		events.Subscribe(func(ev events.QueueLeft) {
+			if err := statsStore.Left(ev.GuildID, ev.Reason == events.LeftVoluntary, ev.At); err != nil {
+				log.Printf("[stats] save error: %v", err)
+			}
+		}),
+		events.Subscribe(func(ev events.QueuePopped) {
+			waits := make([]time.Duration, 0, len(ev.Players))
+			for _, p := range ev.Players {
+				if p.JoinedAt.IsZero() {
+					waits = append(waits, 0)
+					continue
+				}
+				waits = append(waits, ev.At.Sub(p.JoinedAt))
+			}
+			if err := statsStore.Popped(ev.GuildID, waits, ev.At); err != nil {
+				log.Printf("[stats] save error: %v", err)
+			}
+		}),
+	}
+}
+
+// publishLeft announces that a player was removed from a queue without a pop.
+func publishLeft(guildID, channelID, userID, reason string) {
+	events.Publish(events.QueueLeft{GuildID: guildID, ChannelID: channelID, UserID: userID, Reason: reason, At: time.Now().UTC()})
+}
+
+// publishPopped announces the players called from Queue #1 for a match.
+func publishPopped(guildID, channelID, matchID string, popped []queue.Player) {
+	ev := events.QueuePopped{GuildID: guildID, ChannelID: channelID, MatchID: matchID, At: time.Now().UTC()}
+	for _, p := range popped {
+		ev.Players = append(ev.Players, events.PoppedPlayer{UserID: p.ID, JoinedAt: p.JoinedAt})
+	}
+	events.Publish(ev)
+}
+
+// handleQueueStats serves /queuestats [period].
+func handleQueueStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !authorize(s, i, perms.ActionConfigure) {
+		return
+	}
+	_, opts := subcommand(i)
+	period := opts.str("period")
+	span, ok := statsPeriods[period]
+	if !ok {
+		period, span = "week", statsPeriods["week"]
+	}
+	now := time.Now().UTC()
+	sum := statsStore.Summary(i.GuildID, now.Add(-span), now)
+	_ = d.SendEphemeralEmbed(s, i, ui.RenderStatsSummary("Queue stats — last "+period, sum))
+}
+
+var digestOnce sync.Once
+
+// StartStatsDigest posts each guild's last week to its stats channel every
+// Monday (UTC). Guilds without a stats channel are skipped.
+func (b *Bot) StartStatsDigest() {
+	digestOnce.Do(func() {
+		go func() {
+			t := time.NewTicker(time.Hour)
+			defer t.Stop()
+			for ; ; <-t.C {
+				now := time.Now().UTC()
+				for _, g := range b.knownGuilds() {
+					if ch := confOf(g).StatsChannelID; ch != "" {
+						b.postStatsDigest(g, ch, now)
+					}
+				}
+			}
+		}()
+	})
+}
+
+func (b *Bot) postStatsDigest(guildID, channelID string, now time.Time) {
+	if !statsStore.DigestDue(guildID, now) {
+		return
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sum := statsStore.Summary(guildID, today.Add(-7*24*time.Hour), today)
+	if _, err := b.Sess.ChannelMessageSendEmbed(channelID, ui.RenderStatsSummary("Weekly queue digest", sum)); err != nil {
+		log.Printf("[stats] digest post error in %s: %v", guildID, err)
+		return
+	}
+	if err := statsStore.MarkDigest(guildID, now); err != nil {
+		log.Printf("[stats] save error: %v", err)
+	}
+	log.Printf("[stats] weekly digest for %s posted to %s", guildID, channelID)
+}
@@ -0,0 +1,96 @@
+// internal/app/stores.go
+package app
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/accounts"
+	"github.com/jose-valero/popflash-queue-bot/internal/adapters/popflash"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/moderation"
+	"github.com/jose-valero/popflash-queue-bot/internal/notify"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/settings"
+	"github.com/jose-valero/popflash-queue-bot/internal/stats"
+)
+
+var (
+	pfClient         *popflash.Client
+	links, _         = accounts.Open("") // replaced by openStores; in-memory until then
+	strikes, _       = moderation.OpenStrikes("", moderation.DefaultPolicy)
+	bans, _          = moderation.OpenBans("")
+	auditLog, _      = audit.Open("", 0)
+	statsStore, _    = stats.Open("")
+	notifyPrefs, _   = notify.OpenPrefs("")
+	permMatrix, _    = perms.Open("")
+	guildSettings, _ = settings.Open("")
+)
+
+// openStores loads the persistent stores from dataDir. A broken file is
+// logged and the store starts empty instead of blocking the bot. Records
+// saved before their store was per guild are handed to legacyGuild (the
+// config guild_id), the guild the bot used to serve alone.
+func openStores(dataDir, legacyGuild string, pf *popflash.Client) {
+	pfClient = pf
+
+	l, err := accounts.Open(filepath.Join(dataDir, "links.json"))
+	if err != nil {
+		log.Printf("[stores] links load error: %v (starting empty)", err)
+	}
+	links = l
+
+	st, err := moderation.OpenStrikes(filepath.Join(dataDir, "strikes.json"), moderation.DefaultPolicy)
+	if err != nil {
+		log.Printf("[stores] strikes load error: %v (starting empty)", err)
+	}
+	if err := st.Claim(legacyGuild); err != nil {
+		log.Printf("[stores] strikes save error: %v", err)
+	}
+	strikes = st
+
+	bn, err := moderation.OpenBans(filepath.Join(dataDir, "bans.json"))
+	if err != nil {
+		log.Printf("[stores] bans load error: %v (starting empty)", err)
+	}
+	if err := bn.Claim(legacyGuild); err != nil {
+		log.Printf("[stores] bans save error: %v", err)
+	}
+	bans = bn
+
+	al, err := audit.Open(filepath.Join(dataDir, "audit.jsonl"), 1000)
+	if err != nil {
+		log.Printf("[stores] audit load error: %v", err)
+	}
+	if err := al.Claim(legacyGuild); err != nil {
+		log.Printf("[stores] audit save error: %v", err)
+	}
+	auditLog = al
+
+	ss, err := stats.Open(filepath.Join(dataDir, "stats.json"))
+	if err != nil {
+		log.Printf("[stores] stats load error: %v (starting empty)", err)
+	}
+	if err := ss.Claim(legacyGuild); err != nil {
+		log.Printf("[stores] stats save error: %v", err)
+	}
+	statsStore = ss
+
+	np, err := notify.OpenPrefs(filepath.Join(dataDir, "notify.json"))
+	if err != nil {
+		log.Printf("[stores] notify prefs load error: %v (starting empty)", err)
+	}
+	notifyPrefs = np
+
+	pm, err := perms.Open(filepath.Join(dataDir, "permissions.json"))
+	if err != nil {
+		log.Printf("[stores] permissions load error: %v (starting empty)", err)
+	}
+	permMatrix = pm
+
+	gs, err := settings.Open(filepath.Join(dataDir, settings.FileName))
+	if err != nil {
+		log.Printf("[stores] settings load error: %v (starting empty)", err)
+	}
+	guildSettings = gs
+}
@@ -3,13 +3,12 @@ package app
 
 import (
 	"context"
-	"errors"
 	"log"
 	"sync"
 	"time"
 
-	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
 	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/moderation"
 	"github.com/jose-valero/popflash-queue-bot/internal/queue"
 	"github.com/jose-valero/popflash-queue-bot/internal/ui"
 )
@@ -35,95 +34,100 @@ func (b *Bot) StartEventSubscribers() func() {
 
 		// ---------- MATCH STARTED ----------
 		cancels = append(cancels, events.Subscribe(func(ev events.MatchStarted) {
-			channelID := b.Cfg.QueueChannelID
+			channelID := queueChannelOf(ev.GuildID)
+			if channelID == "" {
+				log.Printf("[bus] MatchStarted in guild %s without a queue channel, ignoring", ev.GuildID)
+				return
+			}
 			if recentlyHandled("start:"+channelID, 3*time.Second) {
 				return
 			}
 
 			// Asegura Q#1
-			_, _ = qman.EnsureFirstQueue(channelID, "Queue #1", defaultCapacity)
+			_, _ = qman.EnsureFirstQueue(channelID, "Queue #1", capacityOf(channelID))
 
 			// Opcional: pop de Q#1 al comenzar
-			if popped, _ := qman.PopFromFirst(channelID, defaultCapacity); len(popped) > 0 {
+			var popped []queue.Player
+			_ = serialize(channelID, func() (err error) {
+				popped, err = qman.PopFromFirst(channelID, capacityOf(channelID))
+				return err
+			})
+			if len(popped) > 0 {
 				log.Printf("[bus] auto-pop %d from Queue#1 in %s", len(popped), channelID)
 			}
+			publishPopped(ev.GuildID, channelID, ev.MatchID, popped)
+			startReadyCheck(b.Sess, ev.GuildID, channelID, ev.MatchID, popped)
 
 			// Si hay cliente PF y tenemos MatchID, hidrata y guarda card activa
 			if ev.MatchID != "" {
 				if b.PF != nil {
-					if card, err := b.PF.MatchCard(context.Background(), ev.MatchID); err == nil {
-						ActivePut(card)
+					if card, players, err := b.PF.MatchDetails(context.Background(), ev.MatchID); err == nil {
+						ActivePut(ev.GuildID, card)
 						log.Printf("[bus] active put match=%s map=%s region=%s", ev.MatchID, card.Map, card.Region)
+						b.reconcileMatch(ev, channelID, popped, players)
+						go b.setupTeamVoice(ev, players)
 
 					} else {
 						log.Printf("[bus] PF MatchCard(%s) error: %v — using minimal card", ev.MatchID, err)
-						ActivePut(ui.MatchCard{ID: ev.MatchID, Started: time.Now()})
+						ActivePut(ev.GuildID, ui.MatchCard{ID: ev.MatchID, Started: time.Now()})
 						log.Printf("[bus] active put match=%s map=%s region=%s", ev.MatchID, card.Map, card.Region)
 
 					}
 				} else {
-					ActivePut(ui.MatchCard{ID: ev.MatchID, Started: time.Now()})
+					ActivePut(ev.GuildID, ui.MatchCard{ID: ev.MatchID, Started: time.Now()})
 
 				}
 			}
 
-			// Abrimos la cola
+			// Abrimos la cola (el renderer refresca el embed)
 			SetQueueOpen(channelID, true)
 
-			// Snapshot con fallback
-			qs, err := qman.Queues(channelID)
-			if errors.Is(err, queue.ErrNotFound) {
-				if q, e2 := qman.EnsureFirstQueue(channelID, "Queue #1", defaultCapacity); e2 == nil && q != nil {
-					qs = []*queue.Queue{q}
-					err = nil
-				}
-			}
-			if err == nil {
-				_ = d.PublishOrEditQueueMessage(
-					b.Sess, channelID,
-					ui.RenderQueuesEmbed(qs, true, cardsOrNil(b)),
-					ui.ComponentsForQueues(qs, true),
-				)
-			}
-
 			log.Printf("[bus] MatchStarted → queue OPEN in %s", channelID)
 		}))
 
 		// ---------- MATCH FINISHED ----------
 		cancels = append(cancels, events.Subscribe(func(ev events.MatchFinished) {
-			channelID := b.Cfg.QueueChannelID
+			channelID := queueChannelOf(ev.GuildID)
+			if channelID == "" {
+				return
+			}
 			if recentlyHandled("finish:"+channelID, 3*time.Second) {
 				return
 			}
 
 			// Quita la partida de “activas”
 			if ev.MatchID != "" {
-				ActiveRemove(ev.MatchID)
+				// alimenta el estimador de ETA con la duración de la partida
+				if c, ok := ActiveGet(ev.GuildID, ev.MatchID); ok && !c.Started.IsZero() {
+					qman.RecordMatchDuration(channelID, time.Since(c.Started))
+				}
+				ActiveRemove(ev.GuildID, ev.MatchID)
+				go b.cleanupTeamVoice(ev.MatchID, "finished")
 			}
 
 			// Si aún quedan partidas activas, mantenemos la cola abierta
-			open := ActiveCount() > 0
+			open := ActiveCount(ev.GuildID) > 0
 			SetQueueOpen(channelID, open)
 
-			qs, err := qman.Queues(channelID)
-			if errors.Is(err, queue.ErrNotFound) {
-				if q, e2 := qman.EnsureFirstQueue(channelID, "Queue #1", defaultCapacity); e2 == nil && q != nil {
-					qs = []*queue.Queue{q}
-					err = nil
-				}
-			}
-			if err == nil {
-				_ = d.PublishOrEditQueueMessage(
-					b.Sess, channelID,
-					ui.RenderQueuesEmbed(qs, open, cardsOrNil(b)),
-					ui.ComponentsForQueues(qs, open),
-				)
-			}
-
 			log.Printf("[bus] MatchFinished → queue %s in %s",
 				map[bool]string{true: "OPEN", false: "CLOSED"}[open], channelID)
 		}))
 
+		// ---------- STATS ----------
+		cancels = append(cancels, subscribeStats()...)
+
+		// ---------- NOTIFICATIONS (popped) ----------
+		cancels = append(cancels, b.subscribeNotify())
+
+		// ---------- NO-SHOW ----------
+		cancels = append(cancels, events.Subscribe(func(ev events.PlayerNoShow) {
+			cd, err := strikes.Add(ev.GuildID, ev.UserID, moderation.ReasonNoShow, "match #"+ev.MatchID, time.Now())
+			if err != nil {
+				log.Printf("[bus] strike save error: %v", err)
+			}
+			log.Printf("[bus] no-show strike for %s (match=%s, cooldown %s)", ev.UserID, ev.MatchID, cd)
+		}))
+
 		log.Printf("[bus] subscribers registered (once)")
 
 		subsCancel = func() {
@@ -135,9 +139,9 @@ func (b *Bot) StartEventSubscribers() func() {
 
 	return subsCancel
 }
-func cardsOrNil(b *Bot) []ui.MatchCard {
-	if b.Cfg != nil && b.Cfg.FFActiveMatchesUI {
-		return ActiveList()
+func cardsOrNil(b *Bot, guildID string) []ui.MatchCard {
+	if cfg := b.Cfg(); cfg != nil && cfg.FFActiveMatchesUI {
+		return ActiveList(guildID)
 	}
 	return nil
 }
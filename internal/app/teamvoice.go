@@ -0,0 +1,136 @@
+// internal/app/teamvoice.go
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+	"github.com/jose-valero/popflash-queue-bot/internal/domain/match"
+)
+
+// teamVoiceTimeout deletes team channels whose MatchFinished never arrived.
+const teamVoiceTimeout = 3 * time.Hour
+
+// teamVoice is the pair of temporary channels of one match.
+type teamVoice struct {
+	guildID  string
+	channels []string
+	timer    *time.Timer
+}
+
+var (
+	teamVoiceMu      sync.Mutex
+	teamVoiceByMatch = map[string]*teamVoice{} // matchID -> channels
+)
+
+// setupTeamVoice creates one voice channel per team under an allowed
+// category and moves linked players into their team's channel. Players
+// without a linked PopFlash profile, or not connected to voice, stay put.
+func (b *Bot) setupTeamVoice(ev events.MatchStarted, players []match.Player) {
+	if ev.MatchID == "" || b.Sess == nil {
+		return
+	}
+	guildID := ev.GuildID
+	if guildID == "" {
+		guildID = b.Cfg().GuildID
+	}
+	if !d.TeamVoiceEnabled(guildID) {
+		return
+	}
+
+	teams := map[int][]string{} // team -> discord user IDs
+	for _, p := range players {
+		if l, ok := links.ByPopflashID(p.ID); ok {
+			teams[p.Team] = append(teams[p.Team], l.DiscordID)
+		}
+	}
+	if len(teams) == 0 {
+		log.Printf("[teamvoice] match=%s no linked players, skipping", ev.MatchID)
+		return
+	}
+
+	teamVoiceMu.Lock()
+	if _, ok := teamVoiceByMatch[ev.MatchID]; ok {
+		teamVoiceMu.Unlock()
+		return
+	}
+	tv := &teamVoice{guildID: guildID}
+	teamVoiceByMatch[ev.MatchID] = tv // reserve before the slow API calls
+	teamVoiceMu.Unlock()
+
+	cat, ok := d.TeamVoiceCategory(b.Sess, guildID)
+	if !ok {
+		log.Printf("[teamvoice] no allowed category for team channels in guild %s", guildID)
+		b.cleanupTeamVoice(ev.MatchID, "no category")
+		return
+	}
+	ids, err := d.CreateTeamChannels(b.Sess, guildID, cat, "#"+ev.MatchID, 2, 0)
+
+	teamVoiceMu.Lock()
+	if teamVoiceByMatch[ev.MatchID] != tv {
+		// cleaned up while we were creating (match finished, shutdown):
+		// nobody else knows these channels, drop them now
+		teamVoiceMu.Unlock()
+		if len(ids) > 0 {
+			if err := d.DeleteChannels(b.Sess, ids); err != nil {
+				log.Printf("[teamvoice] match=%s delete orphaned channels: %v", ev.MatchID, err)
+			}
+		}
+		log.Printf("[teamvoice] match=%s ended during setup, channels removed", ev.MatchID)
+		return
+	}
+	tv.channels = ids
+	tv.timer = time.AfterFunc(teamVoiceTimeout, func() { b.cleanupTeamVoice(ev.MatchID, "timeout") })
+	teamVoiceMu.Unlock()
+
+	if err != nil {
+		log.Printf("[teamvoice] match=%s create error: %v", ev.MatchID, err)
+		b.cleanupTeamVoice(ev.MatchID, "create failed")
+		return
+	}
+	for t := 1; t <= len(ids); t++ {
+		moved, err := d.MoveToVoice(b.Sess, guildID, ids[t-1], teams[t])
+		if err != nil {
+			log.Printf("[teamvoice] match=%s move team %d: %v", ev.MatchID, t, err)
+		}
+		log.Printf("[teamvoice] match=%s team %d: moved %d/%d", ev.MatchID, t, moved, len(teams[t]))
+	}
+}
+
+// cleanupTeamVoice deletes the team channels of a match (finished, timed out
+// or failed to set up).
+func (b *Bot) cleanupTeamVoice(matchID, why string) {
+	teamVoiceMu.Lock()
+	tv, ok := teamVoiceByMatch[matchID]
+	delete(teamVoiceByMatch, matchID)
+	teamVoiceMu.Unlock()
+	if !ok {
+		return
+	}
+	if tv.timer != nil {
+		tv.timer.Stop()
+	}
+	if len(tv.channels) == 0 {
+		return
+	}
+	if err := d.DeleteChannels(b.Sess, tv.channels); err != nil {
+		log.Printf("[teamvoice] match=%s delete error: %v", matchID, err)
+	}
+	log.Printf("[teamvoice] match=%s channels deleted (%s)", matchID, why)
+}
+
+// cleanupAllTeamVoice removes every team channel; used on shutdown.
+func (b *Bot) cleanupAllTeamVoice() {
+	teamVoiceMu.Lock()
+	ids := make([]string, 0, len(teamVoiceByMatch))
+	for id := range teamVoiceByMatch {
+		ids = append(ids, id)
+	}
+	teamVoiceMu.Unlock()
+	for _, id := range ids {
+		b.cleanupTeamVoice(id, "shutdown")
+	}
+}
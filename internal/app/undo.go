@@ -0,0 +1,69 @@
+// internal/app/undo.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+// undoAction is the permission needed to undo a checkpoint: the same one
+// that allowed the action itself.
+func undoAction(label string) perms.Action {
+	switch {
+	case label == "kick", label == "fromvoice":
+		return perms.ActionKick
+	case label == "clearmocks":
+		return perms.ActionSeed
+	case strings.HasPrefix(label, "close "):
+		return perms.ActionDelete
+	default: // "reset Q#n"
+		return perms.ActionReset
+	}
+}
+
+// handleUndo reverts the last destructive admin action of the channel.
+// seq pins the undo to a specific action (Undo button); 0 means "latest".
+func handleUndo(s *discordgo.Session, i *discordgo.InteractionCreate, channelID string, seq uint64) {
+	cp, ok := qman.LastCheckpoint(channelID)
+	if !ok {
+		_ = d.SendEphemeral(s, i, "⚠️ Nothing to undo.")
+		return
+	}
+	if !authorize(s, i, undoAction(cp.Label)) {
+		return
+	}
+	if seq == 0 {
+		seq = cp.Seq // undo what was authorized, not something newer
+	}
+
+	var res queue.UndoResult
+	err := audited(s, i, channelID, audit.Entry{Action: audit.ActionUndo}, func() (err error) {
+		res, err = qman.Undo(channelID, seq)
+		return err
+	})
+	switch {
+	case errors.Is(err, queue.ErrNothingToUndo):
+		_ = d.SendEphemeral(s, i, "⚠️ Nothing to undo.")
+		return
+	case errors.Is(err, queue.ErrUndoStale):
+		_ = d.SendEphemeral(s, i, "⚠️ A newer admin action happened since. Use `/queue undo` to revert the latest one.")
+		return
+	case err != nil:
+		_ = d.SendEphemeral(s, i, "⚠️ "+err.Error())
+		return
+	}
+
+	msg := fmt.Sprintf("↩️ Undone: %s.", res.Label)
+	if res.Merged {
+		msg += " The queue changed since, so restored players were merged with the current lineup."
+	}
+	_ = d.SendEphemeral(s, i, msg)
+}
@@ -0,0 +1,135 @@
+// internal/app/voicewatch.go
+package app
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	d "github.com/jose-valero/popflash-queue-bot/internal/adapters/discord"
+	events "github.com/jose-valero/popflash-queue-bot/internal/domain/events"
+)
+
+// voiceGrace holds the pending removals, keyed "channelID:userID" (voice
+// policy) or "lobby:channelID:userID" (lobby auto-join, see lobby.go).
+var voiceGrace = struct {
+	sync.Mutex
+	timers map[string]*time.Timer
+}{timers: map[string]*time.Timer{}}
+
+// onVoiceState enforces VOICE_REQUIRE_TO_JOIN for players already queued:
+// leaving allowed voice (or landing in AFK) starts a grace timer and warns
+// the player; coming back cancels it; otherwise they are removed.
+func (b *Bot) onVoiceState(s *discordgo.Session, ev *discordgo.VoiceStateUpdate) {
+	if ev == nil || ev.VoiceState == nil || !d.VoiceRequireToJoin() {
+		return
+	}
+	uid, guildID := ev.UserID, ev.GuildID
+	channelID := queueChannelOf(guildID)
+	if channelID == "" {
+		return
+	}
+	key := channelID + ":" + uid
+
+	if voiceOK(s, guildID, ev.ChannelID) {
+		if stopGrace(key) {
+			log.Printf("[voice] %s back in voice, grace cancelled", uid)
+		}
+		return
+	}
+	if _, _, err := qman.Position(channelID, uid); err != nil {
+		return // not queued
+	}
+	// only react to actual changes (mute/deafen also fire this event)
+	if ev.BeforeUpdate != nil && ev.BeforeUpdate.ChannelID == ev.ChannelID {
+		return
+	}
+
+	grace := d.VoiceGrace(guildID, channelID)
+	if !startGrace(key, grace, func() { b.voiceGraceExpired(guildID, channelID, uid) }) {
+		return
+	}
+
+	log.Printf("[voice] %s left voice while queued, removing in %s", uid, grace)
+	warnPlayer(s, channelID, uid, fmt.Sprintf(
+		"🔇 You left voice while queued in <#%s>. Rejoin a queue voice channel within **%s** or you'll be removed.",
+		channelID, grace.Round(time.Second)))
+}
+
+func (b *Bot) voiceGraceExpired(guildID, channelID, uid string) {
+	if !stopGrace(channelID + ":" + uid) {
+		return // cancelled meanwhile
+	}
+	vch, _ := d.VoiceChannelOf(b.Sess, guildID, uid)
+	if voiceOK(b.Sess, guildID, vch) {
+		return
+	}
+	removed := false
+	_ = serialize(channelID, func() error {
+		if _, err := qman.LeaveAny(channelID, uid); err == nil {
+			removed = true
+		}
+		return nil
+	})
+	if !removed {
+		return
+	}
+	log.Printf("[voice] %s removed from %s (no voice)", uid, channelID)
+	publishLeft(guildID, channelID, uid, events.LeftNoVoice)
+	warnPlayer(b.Sess, channelID, uid, fmt.Sprintf(
+		"👋 You were removed from the queue in <#%s> for not being in voice.", channelID))
+}
+
+// startGrace schedules fn after grace unless key already has a pending timer.
+func startGrace(key string, grace time.Duration, fn func()) bool {
+	voiceGrace.Lock()
+	defer voiceGrace.Unlock()
+	if _, pending := voiceGrace.timers[key]; pending {
+		return false
+	}
+	voiceGrace.timers[key] = time.AfterFunc(grace, fn)
+	return true
+}
+
+// stopGrace cancels a pending removal; false when there was none.
+func stopGrace(key string) bool {
+	voiceGrace.Lock()
+	defer voiceGrace.Unlock()
+	t, ok := voiceGrace.timers[key]
+	if ok {
+		t.Stop()
+		delete(voiceGrace.timers, key)
+	}
+	return ok
+}
+
+// voiceOK: in the lobby or an allowed voice channel that isn't the AFK one.
+func voiceOK(s *discordgo.Session, guildID, voiceChannelID string) bool {
+	if voiceChannelID != "" && voiceChannelID == d.LobbyChannelID(guildID) {
+		return true
+	}
+	return voiceChannelID != "" &&
+		d.ChannelAllowedByCategory(s, voiceChannelID) &&
+		!d.IsAFKChannel(s, guildID, voiceChannelID)
+}
+
+// warnPlayer DMs the player and falls back to a mention in the queue
+// channel. Unlike notices this ignores /notify: it's about their spot.
+func warnPlayer(s *discordgo.Session, channelID, uid, msg string) {
+	if s == nil {
+		return
+	}
+	err := d.SendDM(s, uid, msg)
+	if err == nil {
+		return
+	}
+	if !d.IsDMBlocked(err) {
+		log.Printf("[voice] dm %s: %v", uid, err)
+	}
+	if err := d.SendMentions(s, channelID, []string{uid}, msg); err != nil {
+		log.Printf("[voice] mention in %s: %v", channelID, err)
+	}
+}
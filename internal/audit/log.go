@@ -0,0 +1,290 @@
+// Package audit - log.go
+// Append-only record of privileged actions (who did what to whom, and how
+// the queues looked before/after).
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+// Retention is how long entries are kept on disk. Older lines are dropped
+// when the log is opened and, while running, once a day on Record.
+const Retention = 180 * 24 * time.Hour
+
+// Action names a privileged operation.
+type Action string
+
+const (
+	ActionOpen        Action = "open"
+	ActionReset       Action = "reset"
+	ActionClose       Action = "close"
+	ActionKick        Action = "kick"
+	ActionSeed        Action = "seed"
+	ActionClearMocks  Action = "clearmocks"
+	ActionBan         Action = "ban"
+	ActionUnban       Action = "unban"
+	ActionStrikeAdd   Action = "strike-add"
+	ActionStrikeClear Action = "strike-clear"
+	ActionLink        Action = "link"
+	ActionUnlink      Action = "unlink"
+	ActionUndo        Action = "undo"
+	ActionFromVoice   Action = "fromvoice"
+	ActionPermissions Action = "permissions"
+	ActionSetup       Action = "setup"
+)
+
+// Actions lists every action, in the order offered by /auditlog.
+var Actions = []Action{
+	ActionOpen, ActionReset, ActionClose, ActionKick, ActionSeed, ActionClearMocks,
+	ActionBan, ActionUnban, ActionStrikeAdd, ActionStrikeClear, ActionLink, ActionUnlink, ActionUndo,
+	ActionFromVoice, ActionPermissions, ActionSetup,
+}
+
+// QueueState is the compact, persisted view of one queue.
+type QueueState struct {
+	Name     string   `json:"name"`
+	Capacity int      `json:"capacity"`
+	Players  []string `json:"players"` // usernames, in order
+}
+
+// Snapshot is the state of every queue of a channel at one point in time.
+type Snapshot []QueueState
+
+// SnapshotOf converts manager snapshots into an audit snapshot.
+func SnapshotOf(qs []*queue.Queue) Snapshot {
+	out := make(Snapshot, 0, len(qs))
+	for _, q := range qs {
+		st := QueueState{Name: q.Name, Capacity: q.Capacity, Players: make([]string, 0, len(q.Players))}
+		for _, p := range q.Players {
+			st.Players = append(st.Players, p.Username)
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// Entry is one audited action.
+type Entry struct {
+	ID         int64     `json:"id"`
+	At         time.Time `json:"at"`
+	Action     Action    `json:"action"`
+	GuildID    string    `json:"guild_id,omitempty"` // "" = written before the log was per guild, see Claim
+	ChannelID  string    `json:"channel_id,omitempty"`
+	ActorID    string    `json:"actor_id"`
+	ActorName  string    `json:"actor_name"`
+	TargetID   string    `json:"target_id,omitempty"`
+	TargetName string    `json:"target_name,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	Before     Snapshot  `json:"before,omitempty"`
+	After      Snapshot  `json:"after,omitempty"`
+}
+
+// Filter narrows Query results; zero fields match everything.
+type Filter struct {
+	GuildID  string
+	Action   Action
+	ActorID  string
+	TargetID string
+	Since    time.Time
+	Limit    int
+}
+
+func (f Filter) match(e Entry) bool {
+	return (f.GuildID == "" || e.GuildID == f.GuildID) &&
+		(f.Action == "" || e.Action == f.Action) &&
+		(f.ActorID == "" || e.ActorID == f.ActorID) &&
+		(f.TargetID == "" || e.TargetID == f.TargetID) &&
+		(f.Since.IsZero() || !e.At.Before(f.Since))
+}
+
+// Log keeps the latest entries in memory and appends every entry to a
+// JSON-lines file, compacted down to Retention.
+type Log struct {
+	mu        sync.Mutex
+	path      string
+	max       int
+	entries   []Entry
+	nextID    int64
+	compacted time.Time // last rewrite (or Open)
+}
+
+// Open loads the tail of the log at path (up to max entries in memory).
+// An empty path yields an in-memory log.
+func Open(path string, max int) (*Log, error) {
+	if max <= 0 {
+		max = 1000
+	}
+	now := time.Now().UTC()
+	l := &Log{path: path, max: max, nextID: 1, compacted: now}
+	if path == "" {
+		return l, nil
+	}
+	cutoff := now.Add(-Retention)
+	err := l.rewriteLocked(func(e *Entry) bool {
+		if e.At.Before(cutoff) {
+			return false
+		}
+		l.push(*e)
+		if e.ID >= l.nextID {
+			l.nextID = e.ID + 1
+		}
+		return true
+	})
+	return l, err
+}
+
+// Claim assigns the entries without a guild to guildID (the guild the bot
+// served alone before the log was per guild), in memory and on disk.
+func (l *Log) Claim(guildID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	claimed := false
+	for k := range l.entries {
+		if l.entries[k].GuildID == "" {
+			l.entries[k].GuildID = guildID
+			claimed = true
+		}
+	}
+	if !claimed {
+		return nil
+	}
+	return l.rewriteLocked(func(e *Entry) bool {
+		if e.GuildID == "" {
+			e.GuildID = guildID
+		}
+		return true
+	})
+}
+
+// rewriteLocked streams the file through keep (which may edit the entry)
+// and replaces it with the result if anything changed. Torn lines are
+// dropped. A missing file or in-memory log is a no-op.
+func (l *Log) rewriteLocked(keep func(*Entry) bool) error {
+	if l.path == "" {
+		return nil
+	}
+	in, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), "."+filepath.Base(l.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	w := bufio.NewWriter(tmp)
+	changed := false
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			changed = true // skip torn lines
+			continue
+		}
+		before := e
+		if !keep(&e) {
+			changed = true
+			continue
+		}
+		line := sc.Bytes()
+		if e.GuildID != before.GuildID {
+			changed = true
+			if line, err = json.Marshal(e); err != nil {
+				_ = tmp.Close()
+				return err
+			}
+		}
+		_, _ = w.Write(line)
+		_ = w.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil || !changed {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
+
+// Record stamps e with an id and time, keeps it and appends it to disk.
+// The stamped entry is returned even if the write fails.
+func (l *Log) Record(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.ID = l.nextID
+	l.nextID++
+	if e.At.IsZero() {
+		e.At = time.Now().UTC()
+	}
+	l.push(e)
+
+	if l.path == "" {
+		return e, nil
+	}
+	if time.Since(l.compacted) > 24*time.Hour {
+		l.compacted = time.Now().UTC()
+		cutoff := l.compacted.Add(-Retention)
+		if err := l.rewriteLocked(func(old *Entry) bool { return !old.At.Before(cutoff) }); err != nil {
+			return e, err
+		}
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return e, err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return e, err
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return e, err
+	}
+	defer f.Close()
+	_, err = f.Write(append(raw, '\n'))
+	return e, err
+}
+
+// Query returns matching entries, newest first.
+func (l *Log) Query(f Filter) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var out []Entry
+	for k := len(l.entries) - 1; k >= 0; k-- {
+		if f.match(l.entries[k]) {
+			out = append(out, l.entries[k])
+			if f.Limit > 0 && len(out) == f.Limit {
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (l *Log) push(e Entry) {
+	l.entries = append(l.entries, e)
+	if over := len(l.entries) - l.max; over > 0 {
+		l.entries = append([]Entry(nil), l.entries[over:]...)
+	}
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordQueryAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, _ := Open(path, 10)
+
+	_, _ = l.Record(Entry{Action: ActionReset, ActorID: "a1", Before: Snapshot{{Name: "Q1", Players: []string{"x"}}}})
+	_, _ = l.Record(Entry{Action: ActionKick, ActorID: "a2", TargetID: "t1"})
+	_, _ = l.Record(Entry{Action: ActionKick, ActorID: "a1", TargetID: "t2"})
+
+	if got := l.Query(Filter{Action: ActionKick}); len(got) != 2 || got[0].TargetID != "t2" {
+		t.Fatalf("kick filter, newest first: %+v", got)
+	}
+	if got := l.Query(Filter{ActorID: "a1", Limit: 1}); len(got) != 1 || got[0].Action != ActionKick {
+		t.Fatalf("actor+limit: %+v", got)
+	}
+	if got := l.Query(Filter{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Fatalf("since in the future should be empty: %+v", got)
+	}
+
+	l2, err := Open(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := l2.Query(Filter{})
+	if len(all) != 3 || all[2].Before[0].Players[0] != "x" {
+		t.Fatalf("reload lost data: %+v", all)
+	}
+	if e, _ := l2.Record(Entry{Action: ActionOpen}); e.ID != 4 {
+		t.Fatalf("ids must continue after reload, got %d", e.ID)
+	}
+}
+
+func TestBounded(t *testing.T) {
+	l, _ := Open("", 2)
+	for k := 0; k < 5; k++ {
+		_, _ = l.Record(Entry{Action: ActionSeed})
+	}
+	if got := l.Query(Filter{}); len(got) != 2 || got[0].ID != 5 {
+		t.Fatalf("want last 2 entries, got %+v", got)
+	}
+}
+
+func TestQueryPerGuild(t *testing.T) {
+	l, _ := Open("", 10)
+	_, _ = l.Record(Entry{Action: ActionKick}) // legacy, no guild
+	_, _ = l.Record(Entry{Action: ActionKick, GuildID: "g2"})
+	l.Claim("g1")
+
+	if got := l.Query(Filter{GuildID: "g1"}); len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("g1 should see the claimed entry only: %+v", got)
+	}
+	if got := l.Query(Filter{GuildID: "g2"}); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("g2 should see its own entry only: %+v", got)
+	}
+}
+
+func TestRetentionAndClaimRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, _ := Open(path, 10)
+	_, _ = l.Record(Entry{Action: ActionKick, At: time.Now().Add(-Retention - time.Hour)}) // expired
+	_, _ = l.Record(Entry{Action: ActionBan})                                              // legacy, no guild
+
+	l2, _ := Open(path, 10)
+	if got := l2.Query(Filter{}); len(got) != 1 || got[0].Action != ActionBan {
+		t.Fatalf("expired entry should be dropped on open: %+v", got)
+	}
+	if err := l2.Claim("g1"); err != nil {
+		t.Fatal(err)
+	}
+
+	l3, _ := Open(path, 10)
+	if got := l3.Query(Filter{GuildID: "g1"}); len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("claim should be on disk: %+v", got)
+	}
+}
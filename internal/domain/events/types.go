@@ -1,6 +1,8 @@
 // Package events - types.go
 package events
 
+import "time"
+
 // MatchStarted is emitted when a PopFlash match starts.
 type MatchStarted struct {
 	GuildID   string
@@ -16,3 +18,53 @@ type MatchFinished struct {
 	MessageID string
 	MatchID   string
 }
+
+// PlayerNoShow is emitted when a player popped from Queue #1 is not found
+// in the PopFlash match that started.
+type PlayerNoShow struct {
+	GuildID   string
+	ChannelID string // queue channel
+	UserID    string
+	MatchID   string
+}
+
+// QueueJoined is emitted when a player joins a queue.
+type QueueJoined struct {
+	GuildID   string
+	ChannelID string
+	UserID    string
+	At        time.Time
+}
+
+// Reasons a player can be removed from a queue (QueueLeft.Reason).
+const (
+	LeftVoluntary = "leave"    // left on their own
+	LeftKicked    = "kick"     // removed by an admin
+	LeftBanned    = "ban"      // removed by a queue ban
+	LeftInMatch   = "in-match" // already playing the match that started
+	LeftNoVoice   = "no-voice" // out of allowed voice past the grace period
+)
+
+// QueueLeft is emitted when a player is removed from a queue without being popped.
+type QueueLeft struct {
+	GuildID   string
+	ChannelID string
+	UserID    string
+	Reason    string // one of the Left* constants
+	At        time.Time
+}
+
+// PoppedPlayer is one player called from Queue #1.
+type PoppedPlayer struct {
+	UserID   string
+	JoinedAt time.Time
+}
+
+// QueuePopped is emitted when players are popped from Queue #1 for a match.
+type QueuePopped struct {
+	GuildID   string
+	ChannelID string
+	MatchID   string
+	Players   []PoppedPlayer
+	At        time.Time
+}
@@ -12,3 +12,17 @@ type Card struct {
 	Score1  *int
 	Score2  *int
 }
+
+// Player is a PopFlash user taking part in a match.
+type Player struct {
+	ID   string // PopFlash user id
+	Name string
+	Team int // 1 or 2
+}
+
+// Profile is the public part of a PopFlash user profile.
+type Profile struct {
+	ID   string
+	Name string
+	Bio  string
+}
@@ -0,0 +1,187 @@
+// Package moderation - bans.go
+// Queue bans (permanent or timed) persisted to disk.
+package moderation
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// Ban keeps a user out of one guild's queues until ExpiresAt (zero =
+// permanent).
+type Ban struct {
+	GuildID   string    `json:"guild_id,omitempty"` // "" = saved before bans were per guild, see Claim
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	IssuedBy  string    `json:"issued_by"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Permanent reports whether the ban has no expiry.
+func (b Ban) Permanent() bool { return b.ExpiresAt.IsZero() }
+
+func (b Ban) expired(now time.Time) bool { return !b.Permanent() && !now.Before(b.ExpiresAt) }
+
+// Bans is a concurrency-safe ban list mirrored to a JSON file.
+type Bans struct {
+	mu     sync.Mutex
+	path   string
+	byUser map[member]Ban
+}
+
+// OpenBans loads bans from path. An empty path yields an in-memory list.
+func OpenBans(path string) (*Bans, error) {
+	b := &Bans{path: path, byUser: make(map[member]Ban)}
+	var saved []Ban
+	if err := storage.LoadJSON(path, &saved); err != nil {
+		return b, err
+	}
+	for _, ban := range saved {
+		b.byUser[member{ban.GuildID, ban.UserID}] = ban
+	}
+	return b, nil
+}
+
+// Claim assigns the bans saved without a guild to guildID (the guild the
+// bot served alone before bans were per guild). When the user is also
+// banned there already, the ban that lasts longer is kept.
+func (b *Bans) Claim(guildID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var legacy []member
+	for k := range b.byUser {
+		if k.guildID == "" {
+			legacy = append(legacy, k)
+		}
+	}
+	if len(legacy) == 0 {
+		return nil
+	}
+	for _, k := range legacy {
+		ban := b.byUser[k]
+		delete(b.byUser, k)
+		ban.GuildID = guildID
+		to := member{guildID, k.userID}
+		if cur, ok := b.byUser[to]; ok && !outlasts(ban, cur) {
+			continue
+		}
+		b.byUser[to] = ban
+	}
+	return b.saveLocked()
+}
+
+// outlasts reports whether a ends after c.
+func outlasts(a, c Ban) bool {
+	switch {
+	case c.Permanent():
+		return false
+	case a.Permanent():
+		return true
+	}
+	return a.ExpiresAt.After(c.ExpiresAt)
+}
+
+// Put adds or replaces the ban of ban.UserID in ban.GuildID. If the save
+// fails the previous state is kept and the error returned.
+func (b *Bans) Put(ban Ban) error {
+	if ban.UserID == "" {
+		return ErrInvalid
+	}
+	if ban.IssuedAt.IsZero() {
+		ban.IssuedAt = time.Now().UTC()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	k := member{ban.GuildID, ban.UserID}
+	prev, had := b.byUser[k]
+	b.byUser[k] = ban
+	if err := b.saveLocked(); err != nil {
+		// Not on disk, so not in force either: a restart would lose it.
+		if had {
+			b.byUser[k] = prev
+		} else {
+			delete(b.byUser, k)
+		}
+		return err
+	}
+	return nil
+}
+
+// Remove lifts the ban of a user in a guild.
+func (b *Bans) Remove(guildID, userID string) (Ban, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	k := member{guildID, userID}
+	ban, ok := b.byUser[k]
+	if !ok {
+		return Ban{}, ErrNotBanned
+	}
+	delete(b.byUser, k)
+	return ban, b.saveLocked()
+}
+
+// Active returns the ban of a user in a guild if it is still in force.
+func (b *Bans) Active(guildID, userID string, now time.Time) (Ban, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ban, ok := b.byUser[member{guildID, userID}]
+	if !ok || ban.expired(now) {
+		return Ban{}, false
+	}
+	return ban, true
+}
+
+// List returns a guild's bans still in force, soonest expiry first
+// (permanent last).
+func (b *Bans) List(guildID string, now time.Time) []Ban {
+	b.mu.Lock()
+	var out []Ban
+	for k, ban := range b.byUser {
+		if k.guildID == guildID && !ban.expired(now) {
+			out = append(out, ban)
+		}
+	}
+	b.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Permanent() != out[j].Permanent() {
+			return !out[i].Permanent()
+		}
+		if out[i].Permanent() {
+			return out[i].IssuedAt.Before(out[j].IssuedAt)
+		}
+		return out[i].ExpiresAt.Before(out[j].ExpiresAt)
+	})
+	return out
+}
+
+// Expire drops every timed ban whose expiry has passed, in every guild, and
+// returns them.
+func (b *Bans) Expire(now time.Time) ([]Ban, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lifted []Ban
+	for k, ban := range b.byUser {
+		if ban.expired(now) {
+			lifted = append(lifted, ban)
+			delete(b.byUser, k)
+		}
+	}
+	if len(lifted) == 0 {
+		return nil, nil
+	}
+	return lifted, b.saveLocked()
+}
+
+func (b *Bans) saveLocked() error {
+	out := make([]Ban, 0, len(b.byUser))
+	for _, ban := range b.byUser {
+		out = append(out, ban)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].IssuedAt.Before(out[j].IssuedAt) })
+	return storage.SaveJSON(b.path, out)
+}
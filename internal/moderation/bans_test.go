@@ -0,0 +1,105 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTimedBanExpires(t *testing.T) {
+	b, _ := OpenBans("")
+	now := time.Now()
+	_ = b.Put(Ban{GuildID: "g", UserID: "u", ExpiresAt: now.Add(time.Minute)})
+	_ = b.Put(Ban{GuildID: "g", UserID: "p"}) // permanent
+
+	if _, ok := b.Active("g", "u", now); !ok {
+		t.Fatalf("u should be banned")
+	}
+	lifted, _ := b.Expire(now.Add(2 * time.Minute))
+	if len(lifted) != 1 || lifted[0].UserID != "u" {
+		t.Fatalf("want u lifted, got %+v", lifted)
+	}
+	if _, ok := b.Active("g", "p", now.Add(24*time.Hour)); !ok {
+		t.Fatalf("permanent ban must stay")
+	}
+}
+
+func TestBansPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+	b, _ := OpenBans(path)
+	_ = b.Put(Ban{GuildID: "g", UserID: "u", Reason: "toxic", IssuedBy: "admin"})
+
+	b2, err := OpenBans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ban, ok := b2.Active("g", "u", time.Now())
+	if !ok || ban.Reason != "toxic" {
+		t.Fatalf("unexpected ban after reload: %+v", ban)
+	}
+}
+
+func TestBansPerGuild(t *testing.T) {
+	b, _ := OpenBans("")
+	now := time.Now()
+	_ = b.Put(Ban{GuildID: "g1", UserID: "u"})
+
+	if _, ok := b.Active("g2", "u", now); ok {
+		t.Fatal("a ban in g1 must not apply in g2")
+	}
+	if got := b.List("g2", now); len(got) != 0 {
+		t.Fatalf("g2 should list no bans, got %+v", got)
+	}
+	if _, err := b.Remove("g2", "u"); err != ErrNotBanned {
+		t.Fatalf("removing from g2: want ErrNotBanned, got %v", err)
+	}
+	if _, ok := b.Active("g1", "u", now); !ok {
+		t.Fatal("g1 ban must stay")
+	}
+}
+
+func TestBansClaimLegacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+	legacy := `[{"user_id":"u","issued_by":"admin","issued_at":"2026-01-01T00:00:00Z"},
+		{"user_id":"v","issued_by":"admin","issued_at":"2026-01-01T00:00:00Z","expires_at":"2099-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenBans(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = b.Put(Ban{GuildID: "g", UserID: "u", ExpiresAt: time.Now().Add(time.Hour)})
+	_ = b.Put(Ban{GuildID: "g", UserID: "v", Reason: "kept"}) // permanent
+	if err := b.Claim("g"); err != nil {
+		t.Fatal(err)
+	}
+
+	b2, _ := OpenBans(path)
+	now := time.Now()
+	if ban, ok := b2.Active("g", "u", now); !ok || !ban.Permanent() {
+		t.Fatalf("the permanent legacy ban should replace the timed one: %+v %v", ban, ok)
+	}
+	if ban, ok := b2.Active("g", "v", now); !ok || ban.Reason != "kept" {
+		t.Fatalf("the permanent guild ban should be kept: %+v %v", ban, ok)
+	}
+	if got := b2.List("", now); len(got) != 0 {
+		t.Fatalf("no ban should stay without a guild: %+v", got)
+	}
+}
+
+func TestBanPutFailureNotInForce(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := OpenBans(filepath.Join(blocker, "bans.json")) // parent is a file: saves fail
+	if err := b.Put(Ban{GuildID: "g", UserID: "u"}); err == nil {
+		t.Fatalf("want save error")
+	}
+	if _, ok := b.Active("g", "u", time.Now()); ok {
+		t.Fatalf("unsaved ban must not be in force")
+	}
+}
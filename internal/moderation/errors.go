@@ -0,0 +1,13 @@
+// Package moderation - errors.go
+package moderation
+
+// merr mirrors queue.qerr: comparable constants usable with errors.Is.
+type merr string
+
+func (e merr) Error() string { return string(e) }
+
+var (
+	ErrInvalid   = merr("invalid moderation entry")
+	ErrNoStrikes = merr("user has no strikes")
+	ErrNotBanned = merr("user is not banned")
+)
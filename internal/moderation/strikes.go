@@ -0,0 +1,212 @@
+// Package moderation - strikes.go
+// Strike bookkeeping and escalating join cooldowns.
+package moderation
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// Reason tells why a strike was issued.
+type Reason string
+
+const (
+	ReasonReadyCheck Reason = "ready-check" // failed to confirm when called
+	ReasonNoShow     Reason = "no-show"     // popped but never joined the match
+	ReasonLeaveSpam  Reason = "leave-spam"  // left the queue too often in a short window
+	ReasonManual     Reason = "manual"      // issued by an admin
+)
+
+// Strike is a single recorded offence, counted in the guild it happened in.
+type Strike struct {
+	GuildID string    `json:"guild_id,omitempty"` // "" = saved before strikes were per guild, see Claim
+	UserID  string    `json:"user_id"`
+	Reason  Reason    `json:"reason"`
+	Ref     string    `json:"ref,omitempty"` // match id, admin id, ...
+	At      time.Time `json:"at"`
+}
+
+// Policy controls how strikes decay and how hard cooldowns escalate.
+type Policy struct {
+	Lifetime    time.Duration   // strikes older than this no longer count
+	Ladder      []time.Duration // cooldown after the 1st, 2nd, ... active strike (last one repeats)
+	LeaveLimit  int             // leaves allowed inside LeaveWindow before a strike
+	LeaveWindow time.Duration
+}
+
+// DefaultPolicy: 5m → 15m → 1h → 24h, strikes live a week,
+// 3 leaves in 10 minutes is a strike.
+var DefaultPolicy = Policy{
+	Lifetime:    7 * 24 * time.Hour,
+	Ladder:      []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour, 24 * time.Hour},
+	LeaveLimit:  3,
+	LeaveWindow: 10 * time.Minute,
+}
+
+// member is a user within one guild; strikes and bans are keyed by it.
+type member struct{ guildID, userID string }
+
+// Strikes is a concurrency-safe strike registry mirrored to a JSON file.
+type Strikes struct {
+	mu     sync.Mutex
+	path   string
+	policy Policy
+	byUser map[member][]Strike
+	leaves map[member][]time.Time // in-memory only, recent leave timestamps
+}
+
+// OpenStrikes loads strikes from path. An empty path yields an in-memory registry.
+func OpenStrikes(path string, p Policy) (*Strikes, error) {
+	s := &Strikes{
+		path:   path,
+		policy: p,
+		byUser: make(map[member][]Strike),
+		leaves: make(map[member][]time.Time),
+	}
+	var saved []Strike
+	if err := storage.LoadJSON(path, &saved); err != nil {
+		return s, err
+	}
+	for _, st := range saved {
+		k := member{st.GuildID, st.UserID}
+		s.byUser[k] = append(s.byUser[k], st)
+	}
+	return s, nil
+}
+
+// Claim assigns the strikes saved without a guild to guildID (the guild the
+// bot served alone before strikes were per guild), next to any the user
+// already has there.
+func (s *Strikes) Claim(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var legacy []member
+	for k := range s.byUser {
+		if k.guildID == "" {
+			legacy = append(legacy, k)
+		}
+	}
+	if len(legacy) == 0 {
+		return nil
+	}
+	for _, k := range legacy {
+		to := member{guildID, k.userID}
+		for _, st := range s.byUser[k] {
+			st.GuildID = guildID
+			s.byUser[to] = append(s.byUser[to], st)
+		}
+		delete(s.byUser, k)
+	}
+	return s.saveLocked()
+}
+
+// Add records a strike in a guild and returns the cooldown it triggers.
+func (s *Strikes) Add(guildID, userID string, r Reason, ref string, now time.Time) (time.Duration, error) {
+	if userID == "" {
+		return 0, ErrInvalid
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := member{guildID, userID}
+	s.byUser[k] = append(s.byUser[k], Strike{GuildID: guildID, UserID: userID, Reason: r, Ref: ref, At: now.UTC()})
+	return s.cooldownLocked(k, now), s.saveLocked()
+}
+
+// Active returns the strikes of a user in a guild that still count.
+func (s *Strikes) Active(guildID, userID string, now time.Time) []Strike {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Strike(nil), s.activeLocked(member{guildID, userID}, now)...)
+}
+
+// Cooldown returns how long the user must still wait before joining the
+// guild's queues.
+func (s *Strikes) Cooldown(guildID, userID string, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cooldownLocked(member{guildID, userID}, now)
+}
+
+// Clear removes every strike of a user in a guild and returns how many were
+// dropped.
+func (s *Strikes) Clear(guildID, userID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := member{guildID, userID}
+	n := len(s.byUser[k])
+	if n == 0 {
+		return 0, ErrNoStrikes
+	}
+	delete(s.byUser, k)
+	delete(s.leaves, k)
+	return n, s.saveLocked()
+}
+
+// RecordLeave notes a voluntary leave. When the user exceeds the leave limit
+// inside the window, a leave-spam strike is issued and its cooldown returned.
+func (s *Strikes) RecordLeave(guildID, userID string, now time.Time) (struck bool, cooldown time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.policy.LeaveLimit <= 0 {
+		return false, 0, nil
+	}
+	k := member{guildID, userID}
+	recent := s.leaves[k][:0]
+	for _, t := range s.leaves[k] {
+		if now.Sub(t) < s.policy.LeaveWindow {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, now)
+	if len(recent) < s.policy.LeaveLimit {
+		s.leaves[k] = recent
+		return false, 0, nil
+	}
+	delete(s.leaves, k)
+	s.byUser[k] = append(s.byUser[k], Strike{GuildID: guildID, UserID: userID, Reason: ReasonLeaveSpam, At: now.UTC()})
+	return true, s.cooldownLocked(k, now), s.saveLocked()
+}
+
+func (s *Strikes) activeLocked(k member, now time.Time) []Strike {
+	all := s.byUser[k]
+	out := make([]Strike, 0, len(all))
+	for _, st := range all {
+		if s.policy.Lifetime <= 0 || now.Sub(st.At) < s.policy.Lifetime {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+func (s *Strikes) cooldownLocked(k member, now time.Time) time.Duration {
+	active := s.activeLocked(k, now)
+	if len(active) == 0 || len(s.policy.Ladder) == 0 {
+		return 0
+	}
+	step := min(len(active), len(s.policy.Ladder)) - 1
+	last := active[0].At
+	for _, st := range active[1:] {
+		if st.At.After(last) {
+			last = st.At
+		}
+	}
+	if left := last.Add(s.policy.Ladder[step]).Sub(now); left > 0 {
+		return left
+	}
+	return 0
+}
+
+func (s *Strikes) saveLocked() error {
+	var out []Strike
+	for _, ss := range s.byUser {
+		out = append(out, ss...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return storage.SaveJSON(s.path, out)
+}
@@ -0,0 +1,102 @@
+package moderation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCooldownEscalates(t *testing.T) {
+	s, _ := OpenStrikes("", DefaultPolicy)
+	now := time.Now()
+
+	cd, _ := s.Add("g", "u", ReasonNoShow, "m1", now)
+	if cd != 5*time.Minute {
+		t.Fatalf("1st strike: want 5m, got %v", cd)
+	}
+	cd, _ = s.Add("g", "u", ReasonNoShow, "m2", now)
+	if cd != 15*time.Minute {
+		t.Fatalf("2nd strike: want 15m, got %v", cd)
+	}
+	if left := s.Cooldown("g", "u", now.Add(10*time.Minute)); left != 5*time.Minute {
+		t.Fatalf("want 5m left, got %v", left)
+	}
+	if left := s.Cooldown("g", "u", now.Add(time.Hour)); left != 0 {
+		t.Fatalf("cooldown should be over, got %v", left)
+	}
+}
+
+func TestStrikesExpire(t *testing.T) {
+	s, _ := OpenStrikes("", DefaultPolicy)
+	old := time.Now().Add(-8 * 24 * time.Hour)
+	_, _ = s.Add("g", "u", ReasonManual, "", old)
+	if n := len(s.Active("g", "u", time.Now())); n != 0 {
+		t.Fatalf("want 0 active, got %d", n)
+	}
+}
+
+func TestLeaveSpam(t *testing.T) {
+	s, _ := OpenStrikes("", DefaultPolicy)
+	now := time.Now()
+	for k := 0; k < 2; k++ {
+		if struck, _, _ := s.RecordLeave("g", "u", now.Add(time.Duration(k)*time.Minute)); struck {
+			t.Fatalf("leave %d should not strike", k+1)
+		}
+	}
+	struck, cd, _ := s.RecordLeave("g", "u", now.Add(2*time.Minute))
+	if !struck || cd <= 0 {
+		t.Fatalf("3rd leave should strike; got %v %v", struck, cd)
+	}
+	// spread-out leaves never strike
+	for k := 0; k < 5; k++ {
+		if struck, _, _ := s.RecordLeave("g", "v", now.Add(time.Duration(k)*time.Hour)); struck {
+			t.Fatalf("spaced leaves should not strike")
+		}
+	}
+}
+
+func TestStrikesPerGuild(t *testing.T) {
+	s, _ := OpenStrikes("", DefaultPolicy)
+	now := time.Now()
+	_, _ = s.Add("g1", "u", ReasonNoShow, "m1", now)
+
+	if left := s.Cooldown("g2", "u", now); left != 0 {
+		t.Fatalf("a strike in g1 must not cool down g2, got %v", left)
+	}
+	if _, err := s.Clear("g2", "u"); err != ErrNoStrikes {
+		t.Fatalf("clearing g2: want ErrNoStrikes, got %v", err)
+	}
+	for k := 0; k < 2; k++ {
+		_, _, _ = s.RecordLeave("g1", "v", now)
+	}
+	if struck, _, _ := s.RecordLeave("g2", "v", now); struck {
+		t.Fatal("leaves in g1 must not count towards g2")
+	}
+}
+
+func TestStrikesClaimMerges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strikes.json")
+	now := time.Now().UTC()
+	legacy := fmt.Sprintf(`[{"user_id":"u","reason":"no-show","at":%q}]`, now.Format(time.RFC3339))
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenStrikes(path, DefaultPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = s.Add("g", "u", ReasonManual, "", now)
+	if err := s.Claim("g"); err != nil {
+		t.Fatal(err)
+	}
+
+	s2, _ := OpenStrikes(path, DefaultPolicy)
+	if n := len(s2.Active("g", "u", now)); n != 2 {
+		t.Fatalf("the legacy strike should join the one already in g, got %d", n)
+	}
+	if n := len(s2.Active("", "u", now)); n != 0 {
+		t.Fatalf("no strike should stay without a guild, got %d", n)
+	}
+}
@@ -0,0 +1,58 @@
+// Package notify - diff.go
+// Detects the queue transitions worth telling a player about.
+package notify
+
+import "github.com/jose-valero/popflash-queue-bot/internal/queue"
+
+// Kind is a queue transition a player can be notified of.
+type Kind string
+
+const (
+	KindReachedFirst Kind = "reached-first" // moved up into Queue #1
+	KindOneAway      Kind = "one-away"      // next in line for Queue #1
+	KindPopped       Kind = "popped"        // called for a match
+)
+
+// Notice is one notification to deliver.
+type Notice struct {
+	UserID string
+	Kind   Kind
+}
+
+type spot struct{ queue, slot int }
+
+func spots(qs []*queue.Queue) map[string]spot {
+	out := make(map[string]spot)
+	for qi, q := range qs {
+		for k, p := range q.Players {
+			out[p.ID] = spot{qi, k}
+		}
+	}
+	return out
+}
+
+// Diff compares two snapshots of a channel and reports players who moved
+// up into Queue #1 or became first of Queue #2 (one spot away). Players who
+// just joined are not notified: they know where they are.
+func Diff(prev, cur []*queue.Queue) []Notice {
+	before := spots(prev)
+	var out []Notice
+	for qi, q := range cur {
+		if qi > 1 {
+			break
+		}
+		for k, p := range q.Players {
+			was, ok := before[p.ID]
+			if !ok {
+				continue
+			}
+			switch {
+			case qi == 0 && was.queue > 0:
+				out = append(out, Notice{UserID: p.ID, Kind: KindReachedFirst})
+			case qi == 1 && k == 0 && (was.queue > 1 || was.slot > 0):
+				out = append(out, Notice{UserID: p.ID, Kind: KindOneAway})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,10 @@
+// Package notify - errors.go
+package notify
+
+// nerr is a constant error like queue's qerr; Prefs.Set returns
+// ErrInvalid for a mode it doesn't know.
+type nerr string
+
+func (e nerr) Error() string { return string(e) }
+
+var ErrInvalid = nerr("invalid notification preference")
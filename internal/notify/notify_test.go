@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/queue"
+)
+
+func lineup(groups ...[]string) []*queue.Queue {
+	out := make([]*queue.Queue, 0, len(groups))
+	for _, g := range groups {
+		q := &queue.Queue{Capacity: 2}
+		for _, id := range g {
+			q.Players = append(q.Players, queue.Player{ID: id})
+		}
+		out = append(out, q)
+	}
+	return out
+}
+
+func TestDiffReachedFirstAndOneAway(t *testing.T) {
+	prev := lineup([]string{"A", "B"}, []string{"C", "D"}, []string{"E"})
+	cur := lineup([]string{"C", "D"}, []string{"E", "F"}) // A,B popped; F joined
+
+	got := map[string]Kind{}
+	for _, n := range Diff(prev, cur) {
+		got[n.UserID] = n.Kind
+	}
+	if len(got) != 3 || got["C"] != KindReachedFirst || got["D"] != KindReachedFirst || got["E"] != KindOneAway {
+		t.Fatalf("unexpected notices %v", got)
+	}
+}
+
+func TestDiffIgnoresNewcomersAndStill(t *testing.T) {
+	prev := lineup([]string{"A"})
+	cur := lineup([]string{"A", "B"})
+	if n := Diff(prev, cur); len(n) != 0 {
+		t.Fatalf("want no notices, got %v", n)
+	}
+}
+
+func TestPrefsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.json")
+	p, _ := OpenPrefs(path)
+	if p.Get("u") != ModeNone {
+		t.Fatalf("default must be none")
+	}
+	_ = p.Set("u", ModeDM)
+	if err := p.Set("u2", Mode("sms")); err != ErrInvalid {
+		t.Fatalf("want ErrInvalid, got %v", err)
+	}
+
+	p2, _ := OpenPrefs(path)
+	if p2.Get("u") != ModeDM {
+		t.Fatalf("dm not persisted")
+	}
+	_ = p2.Set("u", ModeNone)
+	if p3, _ := OpenPrefs(path); p3.Get("u") != ModeNone {
+		t.Fatalf("none must reset")
+	}
+}
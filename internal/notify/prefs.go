@@ -0,0 +1,68 @@
+// Package notify - prefs.go
+// Per-user notification preferences persisted to disk.
+package notify
+
+import (
+	"sync"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// Mode is how a user wants to be told about their queue.
+type Mode string
+
+const (
+	ModeNone    Mode = "none"    // default: no notifications
+	ModeDM      Mode = "dm"      // direct message (falls back to a mention)
+	ModeMention Mode = "mention" // mention in the queue channel
+)
+
+// ParseMode validates a mode name.
+func ParseMode(s string) (Mode, bool) {
+	switch m := Mode(s); m {
+	case ModeNone, ModeDM, ModeMention:
+		return m, true
+	}
+	return "", false
+}
+
+// Prefs is a concurrency-safe user -> Mode map mirrored to a JSON file.
+type Prefs struct {
+	mu     sync.RWMutex
+	path   string
+	byUser map[string]Mode
+}
+
+// OpenPrefs loads preferences from path. An empty path yields an in-memory store.
+func OpenPrefs(path string) (*Prefs, error) {
+	p := &Prefs{path: path, byUser: make(map[string]Mode)}
+	if err := storage.LoadJSON(path, &p.byUser); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// Get returns the user's mode (ModeNone when unset).
+func (p *Prefs) Get(userID string) Mode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if m, ok := p.byUser[userID]; ok {
+		return m
+	}
+	return ModeNone
+}
+
+// Set stores the user's mode; ModeNone forgets the user.
+func (p *Prefs) Set(userID string, m Mode) error {
+	if _, ok := ParseMode(string(m)); !ok || userID == "" {
+		return ErrInvalid
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m == ModeNone {
+		delete(p.byUser, userID)
+	} else {
+		p.byUser[userID] = m
+	}
+	return storage.SaveJSON(p.path, p.byUser)
+}
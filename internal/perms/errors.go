@@ -0,0 +1,12 @@
+// Package perms - errors.go
+package perms
+
+// perr mirrors queue.qerr: comparable constants usable with errors.Is.
+type perr string
+
+func (e perr) Error() string { return string(e) }
+
+var (
+	ErrUnknownAction = perr("unknown action")
+	ErrInvalid       = perr("pick a role or a user")
+)
@@ -0,0 +1,161 @@
+// Package perms - perms.go
+// Per-guild permission matrix: which roles/users may run each admin action.
+// An action nobody was granted falls back to the legacy rule (Administrator
+// or ADMIN_ROLE_IDS); Administrator is always allowed so a guild can't lock
+// itself out.
+package perms
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// Action names a permission-checked operation.
+type Action string
+
+const (
+	ActionOpen      Action = "open"      // open/close the queue (/startqueue)
+	ActionReset     Action = "reset"     // reset a queue, undo
+	ActionDelete    Action = "delete"    // delete a queue
+	ActionKick      Action = "kick"      // kick, /queue fromvoice, /rollcall
+	ActionBan       Action = "ban"       // queue bans and strikes
+	ActionSeed      Action = "seed"      // mock players (/seedqueue, /clearmocks)
+	ActionConfigure Action = "configure" // /permissions, admin links, stats, audit log
+)
+
+// Actions lists every action, in the order shown by /permissions.
+var Actions = []Action{
+	ActionOpen, ActionReset, ActionDelete, ActionKick, ActionBan, ActionSeed, ActionConfigure,
+}
+
+// ParseAction validates an action name.
+func ParseAction(s string) (Action, bool) {
+	a := Action(s)
+	return a, slices.Contains(Actions, a)
+}
+
+// Grant is who may run one action.
+type Grant struct {
+	Roles []string `json:"roles,omitempty"`
+	Users []string `json:"users,omitempty"`
+}
+
+func (g Grant) empty() bool { return len(g.Roles) == 0 && len(g.Users) == 0 }
+
+// Member is the caller as seen by Allowed.
+type Member struct {
+	UserID string
+	Roles  []string
+	Admin  bool // Administrator permission
+	Legacy bool // passes the legacy check (Administrator or ADMIN_ROLE_IDS)
+}
+
+// Matrix is a concurrency-safe guild -> action -> Grant map mirrored to a
+// JSON file.
+type Matrix struct {
+	mu      sync.RWMutex
+	path    string
+	byGuild map[string]map[Action]Grant
+}
+
+// Open loads the matrix from path. An empty path yields an in-memory store.
+func Open(path string) (*Matrix, error) {
+	m := &Matrix{path: path, byGuild: make(map[string]map[Action]Grant)}
+	if err := storage.LoadJSON(path, &m.byGuild); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// Allowed reports whether mem may run action in guildID.
+func (m *Matrix) Allowed(guildID string, action Action, mem Member) bool {
+	if mem.Admin {
+		return true
+	}
+	m.mu.RLock()
+	g := m.byGuild[guildID][action]
+	m.mu.RUnlock()
+	if g.empty() {
+		return mem.Legacy
+	}
+	if slices.Contains(g.Users, mem.UserID) {
+		return true
+	}
+	for _, r := range mem.Roles {
+		if slices.Contains(g.Roles, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the grants configured for a guild (missing actions use the
+// legacy rule).
+func (m *Matrix) Get(guildID string) map[Action]Grant {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[Action]Grant, len(m.byGuild[guildID]))
+	for a, g := range m.byGuild[guildID] {
+		out[a] = Grant{Roles: slices.Clone(g.Roles), Users: slices.Clone(g.Users)}
+	}
+	return out
+}
+
+// Grant adds a role and/or user to action.
+func (m *Matrix) Grant(guildID string, action Action, roleID, userID string) error {
+	return m.update(guildID, action, roleID, userID, func(ids []string, id string) []string {
+		if slices.Contains(ids, id) {
+			return ids
+		}
+		return append(ids, id)
+	})
+}
+
+// Revoke removes a role and/or user from action.
+func (m *Matrix) Revoke(guildID string, action Action, roleID, userID string) error {
+	return m.update(guildID, action, roleID, userID, func(ids []string, id string) []string {
+		return slices.DeleteFunc(ids, func(x string) bool { return x == id })
+	})
+}
+
+// Reset drops every grant of action, back to the legacy rule.
+func (m *Matrix) Reset(guildID string, action Action) error {
+	if _, ok := ParseAction(string(action)); !ok {
+		return ErrUnknownAction
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.byGuild[guildID], action)
+	return storage.SaveJSON(m.path, m.byGuild)
+}
+
+func (m *Matrix) update(guildID string, action Action, roleID, userID string, fn func([]string, string) []string) error {
+	if _, ok := ParseAction(string(action)); !ok {
+		return ErrUnknownAction
+	}
+	if roleID == "" && userID == "" {
+		return ErrInvalid
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	acts, ok := m.byGuild[guildID]
+	if !ok {
+		acts = make(map[Action]Grant)
+		m.byGuild[guildID] = acts
+	}
+	g := acts[action]
+	if roleID != "" {
+		g.Roles = fn(g.Roles, roleID)
+	}
+	if userID != "" {
+		g.Users = fn(g.Users, userID)
+	}
+	if g.empty() {
+		delete(acts, action)
+	} else {
+		acts[action] = g
+	}
+	return storage.SaveJSON(m.path, m.byGuild)
+}
@@ -0,0 +1,73 @@
+package perms
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllowedFallsBackToLegacy(t *testing.T) {
+	m, _ := Open("")
+	if !m.Allowed("g", ActionSeed, Member{UserID: "u", Legacy: true}) {
+		t.Fatal("ungranted action should follow the legacy rule")
+	}
+	if m.Allowed("g", ActionSeed, Member{UserID: "u"}) {
+		t.Fatal("non-legacy member allowed without grants")
+	}
+}
+
+func TestGrantRestrictsAndAdminAlwaysPasses(t *testing.T) {
+	m, _ := Open("")
+	if err := m.Grant("g", ActionSeed, "devs", ""); err != nil {
+		t.Fatal(err)
+	}
+	if m.Allowed("g", ActionSeed, Member{UserID: "u", Legacy: true}) {
+		t.Fatal("legacy admin role should lose seed once it's granted to someone else")
+	}
+	if !m.Allowed("g", ActionSeed, Member{UserID: "u", Roles: []string{"x", "devs"}}) {
+		t.Fatal("role grant ignored")
+	}
+	if !m.Allowed("g", ActionSeed, Member{UserID: "u", Admin: true}) {
+		t.Fatal("Administrator must always pass")
+	}
+	if !m.Allowed("g", ActionReset, Member{UserID: "u", Legacy: true}) {
+		t.Fatal("other actions keep the legacy rule")
+	}
+	if !m.Allowed("other", ActionSeed, Member{UserID: "u", Legacy: true}) {
+		t.Fatal("grants are per guild")
+	}
+
+	_ = m.Grant("g", ActionSeed, "", "u2")
+	if !m.Allowed("g", ActionSeed, Member{UserID: "u2"}) {
+		t.Fatal("user grant ignored")
+	}
+	_ = m.Revoke("g", ActionSeed, "devs", "u2")
+	if _, ok := m.Get("g")[ActionSeed]; ok {
+		t.Fatal("emptied grant should be dropped")
+	}
+}
+
+func TestMatrixPersistsAndValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perms.json")
+	m, _ := Open(path)
+	if err := m.Grant("g", Action("nope"), "r", ""); !errors.Is(err, ErrUnknownAction) {
+		t.Fatalf("want ErrUnknownAction, got %v", err)
+	}
+	if err := m.Grant("g", ActionBan, "", ""); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("want ErrInvalid, got %v", err)
+	}
+	_ = m.Grant("g", ActionBan, "mods", "")
+	_ = m.Grant("g", ActionBan, "mods", "") // no duplicates
+
+	m2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := m2.Get("g")[ActionBan]; len(g.Roles) != 1 || g.Roles[0] != "mods" {
+		t.Fatalf("reloaded grant = %+v", g)
+	}
+	_ = m2.Reset("g", ActionBan)
+	if len(m2.Get("g")) != 0 {
+		t.Fatal("reset should clear the action")
+	}
+}
@@ -0,0 +1,100 @@
+// Package queue - batch.go
+// Transactional bulk operations: many joins/leaves/moves under one lock,
+// one relayout and one version bump.
+package queue
+
+import "time"
+
+// Tx is the working set of a Manager.Batch call. Operations act on a
+// flattened copy of the channel's players; nothing is visible to other
+// callers until the batch function returns nil.
+type Tx struct {
+	players  []Player
+	capacity int
+	now      time.Time
+	undo     string
+	res      BatchResult
+}
+
+// BatchResult summarizes a committed batch.
+type BatchResult struct {
+	Joined, Left int
+	Checkpoint   uint64 // undo sequence, 0 unless Tx.Undoable was called
+	Version      uint64 // channel version after the batch
+}
+
+func (tx *Tx) find(playerID string) int {
+	for k, p := range tx.players {
+		if p.ID == playerID {
+			return k
+		}
+	}
+	return -1
+}
+
+// Join appends the player at the tail of the lineup.
+func (tx *Tx) Join(playerID, username string) error {
+	if tx.find(playerID) >= 0 {
+		return ErrAlreadyIn
+	}
+	tx.players = append(tx.players, Player{ID: playerID, Username: username, JoinedAt: tx.now})
+	tx.res.Joined++
+	return nil
+}
+
+// Leave removes the player from the lineup.
+func (tx *Tx) Leave(playerID string) error {
+	k := tx.find(playerID)
+	if k < 0 {
+		return ErrNotIn
+	}
+	tx.players = append(tx.players[:k], tx.players[k+1:]...)
+	tx.res.Left++
+	return nil
+}
+
+// Players returns the lineup as it stands inside the batch.
+func (tx *Tx) Players() []Player {
+	return append([]Player(nil), tx.players...)
+}
+
+// Undoable records an undo checkpoint for this batch when it commits.
+func (tx *Tx) Undoable(label string) { tx.undo = label }
+
+// Batch runs fn against a private copy of the channel lineup and, if fn
+// returns nil, commits every change at once: a single relayout, a single
+// version bump and (optionally) a single undo checkpoint. If fn fails, the
+// channel is left untouched. capacity is used when the channel has no queues.
+func (m *Manager) Batch(channelID string, capacity int, fn func(*Tx) error) (BatchResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cq := m.getOrCreateChannel(channelID)
+	if len(cq.Queues) > 0 {
+		capacity = cq.Queues[0].Capacity
+	}
+	if capacity <= 0 {
+		capacity = 5
+	}
+
+	tx := &Tx{players: flatten(cq.Queues), capacity: capacity, now: time.Now().UTC()}
+	if err := fn(tx); err != nil {
+		return BatchResult{Version: cq.version}, err
+	}
+	if tx.res.Joined+tx.res.Left == 0 {
+		tx.res.Version = cq.version
+		return tx.res, nil
+	}
+
+	var cp checkpoint
+	if tx.undo != "" {
+		cp = beginCheckpoint(cq, tx.undo)
+	}
+	relayout(channelID, cq, tx.players, capacity)
+	m.bump(cq)
+	if tx.undo != "" {
+		tx.res.Checkpoint = commitCheckpoint(cq, cp)
+	}
+	tx.res.Version = cq.version
+	return tx.res, nil
+}
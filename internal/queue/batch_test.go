@@ -0,0 +1,80 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBatchSingleVersionBump(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	_, _ = m.EnsureFirstQueue(ch, "Q1", 5)
+	v0 := m.Version(ch)
+
+	res, err := m.Batch(ch, 5, func(tx *Tx) error {
+		for k := 0; k < 12; k++ {
+			if err := tx.Join(string(rune('A'+k)), "u"); err != nil {
+				return err
+			}
+		}
+		return tx.Leave("B")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Joined != 12 || res.Left != 1 || res.Version != v0+1 {
+		t.Fatalf("unexpected result %+v (v0=%d)", res, v0)
+	}
+	qs, _ := m.Queues(ch)
+	invariant(t, qs)
+	if len(qs) != 3 || len(qs[0].Players) != 5 || len(qs[2].Players) != 1 {
+		t.Fatalf("want 5/5/1 layout, got %d queues", len(qs))
+	}
+}
+
+func TestBatchRollsBackOnError(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	_, _ = m.JoinAny(ch, "A", "A", 5)
+	v0 := m.Version(ch)
+
+	_, err := m.Batch(ch, 5, func(tx *Tx) error {
+		_ = tx.Join("B", "B")
+		return tx.Leave("nobody")
+	})
+	if !errors.Is(err, ErrNotIn) {
+		t.Fatalf("want ErrNotIn, got %v", err)
+	}
+	qs, _ := m.Queues(ch)
+	if got := strings.Join(ids(qs), ""); got != "A" || m.Version(ch) != v0 {
+		t.Fatalf("batch leaked: %s v=%d", got, m.Version(ch))
+	}
+}
+
+func TestBatchUndo(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		_, _ = m.JoinAny(ch, id, id, 5)
+	}
+	res, err := m.Batch(ch, 5, func(tx *Tx) error {
+		tx.Undoable("cleanup")
+		_ = tx.Leave("B")
+		return tx.Leave("D") // F and G are pulled up into Q1
+	})
+	if err != nil || res.Checkpoint == 0 {
+		t.Fatalf("batch: %+v %v", res, err)
+	}
+	qs, _ := m.Queues(ch)
+	if got := strings.Join(ids(qs), ""); got != "ACEFG" || len(qs) != 1 {
+		t.Fatalf("want ACEFG in one queue, got %s (%d queues)", got, len(qs))
+	}
+	if _, err := m.Undo(ch, res.Checkpoint); err != nil {
+		t.Fatal(err)
+	}
+	qs, _ = m.Queues(ch)
+	if got := strings.Join(ids(qs), ""); got != "ABCDEFG" {
+		t.Fatalf("undo: want ABCDEFG, got %s", got)
+	}
+}
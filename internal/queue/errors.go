@@ -14,4 +14,8 @@ var (
 	ErrFull      = qerr("queue is full")
 	ErrAlreadyIn = qerr("already in queue")
 	ErrNotIn     = qerr("player not in queue")
+
+	ErrNothingToUndo = qerr("nothing to undo")
+	ErrUndoStale     = qerr("a newer action happened since")
+	ErrStale         = qerr("queue changed since this view was rendered")
 )
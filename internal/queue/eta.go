@@ -0,0 +1,117 @@
+// Package queue - eta.go
+// Wait-time estimation from recent pop intervals and match durations.
+package queue
+
+import "time"
+
+const (
+	// etaSamples is how many recent intervals/durations feed the estimate.
+	etaSamples = 10
+	// maxPopGap drops intervals spanning a quiet period (e.g. overnight),
+	// which say nothing about how fast the queue moves while active.
+	maxPopGap = 3 * time.Hour
+)
+
+// etaStats is the per-channel history behind ETA estimates.
+type etaStats struct {
+	lastPop   time.Time
+	intervals []time.Duration // between consecutive pops, newest last
+	durations []time.Duration // of finished matches, newest last
+}
+
+func pushSample(s []time.Duration, d time.Duration) []time.Duration {
+	s = append(s, d)
+	if over := len(s) - etaSamples; over > 0 {
+		s = append([]time.Duration(nil), s[over:]...)
+	}
+	return s
+}
+
+func mean(s []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, d := range s {
+		sum += d
+	}
+	return sum / time.Duration(len(s))
+}
+
+// recordPop notes a pop at t. Caller must hold the write lock.
+func (e *etaStats) recordPop(t time.Time) {
+	if !e.lastPop.IsZero() {
+		if gap := t.Sub(e.lastPop); gap > 0 && gap <= maxPopGap {
+			e.intervals = pushSample(e.intervals, gap)
+		}
+	}
+	e.lastPop = t
+}
+
+// interval is the expected time between pops: the mean recent pop interval,
+// else the mean match duration (a group pops when a server frees up).
+func (e *etaStats) interval() (time.Duration, bool) {
+	switch {
+	case len(e.intervals) > 0:
+		return mean(e.intervals), true
+	case len(e.durations) > 0:
+		return mean(e.durations), true
+	}
+	return 0, false
+}
+
+// RecordMatchDuration feeds the duration of a finished match into the
+// channel's estimates.
+func (m *Manager) RecordMatchDuration(channelID string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cq := m.getOrCreateChannel(channelID)
+	cq.eta.durations = pushSample(cq.eta.durations, d)
+}
+
+// ETAs estimates, for each queue of the channel, how long until it pops:
+// whatever is left of the current interval for Queue #1, plus one interval
+// per queue ahead. ok is false while there is no history to estimate from.
+func (m *Manager) ETAs(channelID string, now time.Time) (etas []time.Duration, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cq, found := m.byChan[channelID]
+	if !found || len(cq.Queues) == 0 {
+		return nil, false
+	}
+	iv, ok := cq.eta.interval()
+	if !ok {
+		return nil, false
+	}
+	first := iv
+	if !cq.eta.lastPop.IsZero() {
+		if first = iv - now.Sub(cq.eta.lastPop); first < 0 {
+			first = 0 // overdue: any moment now
+		}
+	}
+	etas = make([]time.Duration, len(cq.Queues))
+	for k := range etas {
+		etas[k] = first + time.Duration(k)*iv
+	}
+	return etas, true
+}
+
+// Position finds a player: 0-based queue index and 0-based slot within it.
+func (m *Manager) Position(channelID, playerID string) (queueIdx, slot int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cq, ok := m.byChan[channelID]
+	if !ok || len(cq.Queues) == 0 {
+		return 0, 0, ErrNotFound
+	}
+	for qi, q := range cq.Queues {
+		for k, p := range q.Players {
+			if p.ID == playerID {
+				return qi, k, nil
+			}
+		}
+	}
+	return 0, 0, ErrNotIn
+}
@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestETAsFromMatchDurationsThenPops(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for k := 0; k < 12; k++ {
+		_, _ = m.JoinAny(ch, string(rune('A'+k)), "u", 5)
+	}
+	if _, ok := m.ETAs(ch, time.Now()); ok {
+		t.Fatalf("no history: want no estimate")
+	}
+
+	m.RecordMatchDuration(ch, 30*time.Minute)
+	etas, ok := m.ETAs(ch, time.Now())
+	if !ok || len(etas) != 3 || etas[0] != 30*time.Minute || etas[2] != 90*time.Minute {
+		t.Fatalf("duration-based ETAs wrong: %v", etas)
+	}
+
+	// pops 20m apart take over from match durations
+	m.mu.Lock()
+	cq := m.byChan[ch]
+	t0 := time.Now().Add(-25 * time.Minute)
+	cq.eta.recordPop(t0)
+	cq.eta.recordPop(t0.Add(20 * time.Minute))
+	m.mu.Unlock()
+
+	etas, _ = m.ETAs(ch, t0.Add(25*time.Minute))
+	if etas[0] != 15*time.Minute || etas[1] != 35*time.Minute {
+		t.Fatalf("pop-based ETAs wrong: %v", etas)
+	}
+	etas, _ = m.ETAs(ch, t0.Add(50*time.Minute))
+	if etas[0] != 0 {
+		t.Fatalf("overdue Q#1 must be 0, got %v", etas[0])
+	}
+}
+
+func TestPosition(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for k := 0; k < 7; k++ {
+		_, _ = m.JoinAny(ch, string(rune('A'+k)), "u", 5)
+	}
+	if qi, slot, err := m.Position(ch, "G"); err != nil || qi != 1 || slot != 1 {
+		t.Fatalf("want Q2 slot 1, got %d %d %v", qi, slot, err)
+	}
+	if _, _, err := m.Position(ch, "Z"); err != ErrNotIn {
+		t.Fatalf("want ErrNotIn, got %v", err)
+	}
+}
@@ -2,6 +2,11 @@
 // Small internal helpers kept separate to keep manager.go focused.
 package queue
 
+import (
+	"fmt"
+	"time"
+)
+
 // snapshot returns a deep copy of the given queue, copying the Players slice.
 func snapshot(q *Queue) *Queue {
 	cp := *q
@@ -21,3 +26,32 @@ func locatePlayer(qs []*Queue, playerID string) (qi, pi int) {
 	}
 	return -1, -1
 }
+
+// appendQueue creates an empty tail queue in cq and returns it. Queue IDs
+// come from a per-channel counter, so they are never reused even after
+// DeleteAt shifts positions. Caller must hold the Manager mutex.
+func appendQueue(channelID string, cq *channelQueues, capacity int, now time.Time) *Queue {
+	if capacity <= 0 {
+		capacity = 5
+	}
+	newIdx := len(cq.Queues) + 1
+	cq.lastQID++
+	q := &Queue{
+		ID:        fmt.Sprintf("%s:%d", channelID, cq.lastQID),
+		Name:      fmt.Sprintf("Queue #%d", newIdx),
+		Players:   []Player{},
+		CreatedAt: now,
+		Capacity:  capacity,
+	}
+	cq.Queues = append(cq.Queues, q)
+	return q
+}
+
+// flatten returns every queued player in queue order.
+func flatten(qs []*Queue) []Player {
+	var out []Player
+	for _, q := range qs {
+		out = append(out, q.Players...)
+	}
+	return out
+}
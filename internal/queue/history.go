@@ -0,0 +1,134 @@
+// Package queue - history.go
+// Bounded per-channel undo history for destructive admin actions.
+package queue
+
+import "time"
+
+// historyLimit caps how many checkpoints are kept per channel.
+const historyLimit = 10
+
+// checkpoint remembers the queues right before a destructive action.
+type checkpoint struct {
+	seq          uint64
+	label        string
+	at           time.Time
+	before       []*Queue            // deep copy, pre-action
+	after        map[string]struct{} // player IDs present right after the action
+	versionAfter uint64              // channel version right after the action
+}
+
+// Checkpoint is the public view of an undoable action.
+type Checkpoint struct {
+	Seq   uint64
+	Label string
+	At    time.Time
+}
+
+// UndoResult tells what Undo did.
+type UndoResult struct {
+	Label  string
+	Merged bool // the channel changed since the action; state was merged, not replaced
+}
+
+// beginCheckpoint captures the current state. Caller must hold the mutex.
+func beginCheckpoint(cq *channelQueues, label string) checkpoint {
+	before := make([]*Queue, 0, len(cq.Queues))
+	for _, q := range cq.Queues {
+		before = append(before, snapshot(q))
+	}
+	return checkpoint{label: label, at: time.Now().UTC(), before: before}
+}
+
+// commitCheckpoint stores cp once the action has been applied and returns
+// its sequence. Caller must hold the mutex.
+func commitCheckpoint(cq *channelQueues, cp checkpoint) uint64 {
+	cq.nextSeq++
+	cp.seq = cq.nextSeq
+	cp.versionAfter = cq.version
+	cp.after = make(map[string]struct{})
+	for _, p := range flatten(cq.Queues) {
+		cp.after[p.ID] = struct{}{}
+	}
+	cq.history = append(cq.history, cp)
+	if over := len(cq.history) - historyLimit; over > 0 {
+		cq.history = append([]checkpoint(nil), cq.history[over:]...)
+	}
+	return cp.seq
+}
+
+// LastCheckpoint returns the most recent undoable action of a channel.
+func (m *Manager) LastCheckpoint(channelID string) (Checkpoint, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cq, ok := m.byChan[channelID]
+	if !ok || len(cq.history) == 0 {
+		return Checkpoint{}, false
+	}
+	cp := cq.history[len(cq.history)-1]
+	return Checkpoint{Seq: cp.seq, Label: cp.label, At: cp.at}, true
+}
+
+// Undo reverts the latest destructive action of a channel. If seq is not
+// zero it must match that latest action (ErrUndoStale otherwise), so an old
+// "Undo" button cannot revert a newer action.
+//
+// When nothing changed since the action the previous state is restored as is.
+// Otherwise the states are merged: players removed by the action get their
+// old place back, players who left afterwards stay out, and players who
+// joined afterwards keep their relative order behind them.
+func (m *Manager) Undo(channelID string, seq uint64) (UndoResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cq, ok := m.byChan[channelID]
+	if !ok || len(cq.history) == 0 {
+		return UndoResult{}, ErrNothingToUndo
+	}
+	cp := cq.history[len(cq.history)-1]
+	if seq != 0 && seq != cp.seq {
+		return UndoResult{}, ErrUndoStale
+	}
+	cq.history = cq.history[:len(cq.history)-1]
+
+	if cq.version == cp.versionAfter {
+		cq.Queues = cp.before
+		m.bump(cq)
+		return UndoResult{Label: cp.label}, nil
+	}
+
+	current := flatten(cq.Queues)
+	present := make(map[string]Player, len(current))
+	for _, p := range current {
+		present[p.ID] = p
+	}
+
+	merged := make([]Player, 0, len(current))
+	inBefore := make(map[string]struct{})
+	for _, p := range flatten(cp.before) {
+		inBefore[p.ID] = struct{}{}
+		if cur, ok := present[p.ID]; ok {
+			merged = append(merged, cur)
+			continue
+		}
+		if _, survived := cp.after[p.ID]; !survived {
+			merged = append(merged, p) // removed by the action: restore
+		}
+		// else: left after the action, keep them out
+	}
+	for _, p := range current {
+		if _, ok := inBefore[p.ID]; !ok {
+			merged = append(merged, p)
+		}
+	}
+
+	capacity := 5
+	if len(cp.before) > 0 {
+		capacity = cp.before[0].Capacity
+	} else if len(cq.Queues) > 0 {
+		capacity = cq.Queues[0].Capacity
+	}
+	relayout(channelID, cq, merged, capacity)
+	m.bump(cq)
+	return UndoResult{Label: cp.label, Merged: true}, nil
+}
@@ -10,12 +10,19 @@ import (
 
 // Manager keeps per-channel queue sets behind a RWMutex.
 type Manager struct {
-	mu     sync.RWMutex
-	byChan map[string]*channelQueues // channelID -> queues in that channel
+	mu       sync.RWMutex
+	byChan   map[string]*channelQueues // channelID -> queues in that channel
+	watchers []*Watcher
 }
 
 type channelQueues struct {
-	Queues []*Queue // 0-based indexes; UI can render 1-based
+	id      string
+	Queues  []*Queue     // 0-based indexes; UI can render 1-based
+	version uint64       // bumped on every mutation
+	history []checkpoint // bounded undo stack, oldest first
+	nextSeq uint64
+	lastQID uint64 // monotonic queue id counter, never reused
+	eta     etaStats
 }
 
 // NewManager constructs an empty Manager.
@@ -26,7 +33,7 @@ func NewManager() *Manager {
 func (m *Manager) getOrCreateChannel(channelID string) *channelQueues {
 	cq, ok := m.byChan[channelID]
 	if !ok {
-		cq = &channelQueues{Queues: []*Queue{}}
+		cq = &channelQueues{id: channelID, Queues: []*Queue{}}
 		m.byChan[channelID] = cq
 	}
 	return cq
@@ -44,19 +51,41 @@ func (m *Manager) EnsureFirstQueue(channelID, name string, capacity int) (*Queue
 		if capacity <= 0 {
 			return nil, qerr("invalid capacity")
 		}
-		q := &Queue{
-			ID:        fmt.Sprintf("%s:%d", channelID, 1),
-			Name:      name,
-			Players:   []Player{},
-			CreatedAt: time.Now().UTC(),
-			Capacity:  capacity,
-		}
-		cq.Queues = append(cq.Queues, q)
+		q := appendQueue(channelID, cq, capacity, time.Now().UTC())
+		q.Name = name
+		m.bump(cq)
 		return snapshot(q), nil
 	}
 	return snapshot(cq.Queues[0]), nil
 }
 
+// Version returns the channel's change counter (0 if the channel is unknown).
+// It increases on every mutation, so callers can detect stale views.
+func (m *Manager) Version(channelID string) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if cq, ok := m.byChan[channelID]; ok {
+		return cq.version
+	}
+	return 0
+}
+
+// Snapshot is Queues plus the version the snapshot was taken at.
+func (m *Manager) Snapshot(channelID string) ([]*Queue, uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cq, ok := m.byChan[channelID]
+	if !ok || len(cq.Queues) == 0 {
+		return nil, 0, ErrNotFound
+	}
+	out := make([]*Queue, 0, len(cq.Queues))
+	for _, q := range cq.Queues {
+		out = append(out, snapshot(q))
+	}
+	return out, cq.version, nil
+}
+
 // Queues returns a deep-copy snapshot of all queues in a channel.
 func (m *Manager) Queues(channelID string) ([]*Queue, error) {
 	m.mu.RLock()
@@ -96,24 +125,16 @@ func (m *Manager) JoinAny(channelID, playerID, username string, capacity int) (i
 	for idx, q := range cq.Queues {
 		if len(q.Players) < q.Capacity {
 			q.Players = append(q.Players, Player{ID: playerID, Username: username, JoinedAt: now})
+			m.bump(cq)
 			return idx + 1, nil
 		}
 	}
 
 	// Create a new tail queue.
-	if capacity <= 0 {
-		capacity = 5
-	}
-	newIdx := len(cq.Queues) + 1
-	q := &Queue{
-		ID:        fmt.Sprintf("%s:%d", channelID, newIdx),
-		Name:      fmt.Sprintf("Queue #%d", newIdx),
-		Players:   []Player{{ID: playerID, Username: username, JoinedAt: now}},
-		CreatedAt: now,
-		Capacity:  capacity,
-	}
-	cq.Queues = append(cq.Queues, q)
-	return newIdx, nil
+	q := appendQueue(channelID, cq, capacity, now)
+	q.Players = append(q.Players, Player{ID: playerID, Username: username, JoinedAt: now})
+	m.bump(cq)
+	return len(cq.Queues), nil
 }
 
 // LeaveAny removes the player from whichever queue they are in, then
@@ -127,6 +148,32 @@ func (m *Manager) LeaveAny(channelID, playerID string) (int, error) {
 	if !ok {
 		return 0, ErrNotFound
 	}
+	return m.leaveLocked(cq, playerID)
+}
+
+// Kick removes a player like LeaveAny but records an undo checkpoint first.
+// If expect is non-zero it must equal the channel version (ErrStale otherwise).
+// Returns the checkpoint sequence for Undo.
+func (m *Manager) Kick(channelID, playerID string, expect uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cq, ok := m.byChan[channelID]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if expect != 0 && expect != cq.version {
+		return 0, ErrStale
+	}
+	if qi, _ := locatePlayer(cq.Queues, playerID); qi < 0 {
+		return 0, ErrNotIn
+	}
+	cp := beginCheckpoint(cq, "kick")
+	_, _ = m.leaveLocked(cq, playerID)
+	return commitCheckpoint(cq, cp), nil
+}
+
+func (m *Manager) leaveLocked(cq *channelQueues, playerID string) (int, error) {
 	qi, pi := locatePlayer(cq.Queues, playerID)
 	if qi < 0 {
 		return 0, ErrNotIn
@@ -137,40 +184,130 @@ func (m *Manager) LeaveAny(channelID, playerID string) (int, error) {
 
 	rebalanceForward(cq.Queues, qi)
 	cq.Queues = pruneTrailingEmpty(cq.Queues)
+	m.bump(cq)
 
 	return qi + 1, nil
 }
 
 // ResetAt clears only the indicated queue (1-based index) and rebalances.
-func (m *Manager) ResetAt(channelID string, idx int) error {
+// Returns the undo checkpoint sequence.
+func (m *Manager) ResetAt(channelID string, idx int) (uint64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	cq, ok := m.byChan[channelID]
 	if !ok || idx <= 0 || idx > len(cq.Queues) {
-		return ErrNotFound
+		return 0, ErrNotFound
+	}
+	return m.resetLocked(cq, idx), nil
+}
+
+// ResetQueue clears the queue with the given (stable) ID. If expect is
+// non-zero it must equal the channel version (ErrStale otherwise).
+func (m *Manager) ResetQueue(channelID, queueID string, expect uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cq, idx, err := m.lookupQueue(channelID, queueID, expect)
+	if err != nil {
+		return 0, err
 	}
+	return m.resetLocked(cq, idx), nil
+}
+
+func (m *Manager) resetLocked(cq *channelQueues, idx int) uint64 {
+	cp := beginCheckpoint(cq, fmt.Sprintf("reset Q#%d", idx))
 	cq.Queues[idx-1].Players = cq.Queues[idx-1].Players[:0]
 	rebalanceForward(cq.Queues, idx-1)
 	cq.Queues = pruneTrailingEmpty(cq.Queues)
-	return nil
+	m.bump(cq)
+	return commitCheckpoint(cq, cp)
 }
 
 // DeleteAt removes the indicated queue (1-based index) and then rebalances.
 // If the last queues become empty, trailing empties are pruned.
-func (m *Manager) DeleteAt(channelID string, idx int) error {
+// Returns the undo checkpoint sequence.
+func (m *Manager) DeleteAt(channelID string, idx int) (uint64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	cq, ok := m.byChan[channelID]
 	if !ok || idx <= 0 || idx > len(cq.Queues) {
-		return ErrNotFound
+		return 0, ErrNotFound
+	}
+	return m.deleteLocked(cq, idx), nil
+}
+
+// DeleteQueue removes the queue with the given (stable) ID. If expect is
+// non-zero it must equal the channel version (ErrStale otherwise).
+func (m *Manager) DeleteQueue(channelID, queueID string, expect uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cq, idx, err := m.lookupQueue(channelID, queueID, expect)
+	if err != nil {
+		return 0, err
 	}
+	return m.deleteLocked(cq, idx), nil
+}
+
+func (m *Manager) deleteLocked(cq *channelQueues, idx int) uint64 {
+	cp := beginCheckpoint(cq, fmt.Sprintf("close Q#%d", idx))
 	cq.Queues = append(cq.Queues[:idx-1], cq.Queues[idx:]...)
 	// After removing an entire queue, rebalancing from the previous index
 	// keeps earlier queues as full as possible.
 	rebalanceForward(cq.Queues, max(0, idx-2))
 	cq.Queues = pruneTrailingEmpty(cq.Queues)
+	m.bump(cq)
+	return commitCheckpoint(cq, cp)
+}
+
+// lookupQueue resolves a queue ID to its 1-based index, checking the
+// expected version first. Caller must hold the mutex.
+func (m *Manager) lookupQueue(channelID, queueID string, expect uint64) (*channelQueues, int, error) {
+	cq, ok := m.byChan[channelID]
+	if !ok {
+		return nil, 0, ErrNotFound
+	}
+	if expect != 0 && expect != cq.version {
+		return nil, 0, ErrStale
+	}
+	for k, q := range cq.Queues {
+		if q.ID == queueID {
+			return cq, k + 1, nil
+		}
+	}
+	return nil, 0, ErrNotFound
+}
+
+// SetCapacity applies capacity to every queue of the channel and relays the
+// lineup out under it, keeping the order (queues are added or pruned as
+// needed). A channel without queues is left alone.
+func (m *Manager) SetCapacity(channelID string, capacity int) error {
+	if capacity <= 0 {
+		return qerr("invalid capacity")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cq, ok := m.byChan[channelID]
+	if !ok || len(cq.Queues) == 0 {
+		return nil
+	}
+	changed := false
+	for _, q := range cq.Queues {
+		if q.Capacity != capacity {
+			q.Capacity = capacity
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if players := flatten(cq.Queues); len(players) > 0 {
+		relayout(channelID, cq, players, capacity)
+	}
+	m.bump(cq)
 	return nil
 }
 
@@ -200,6 +337,10 @@ func (m *Manager) PopFromFirst(channelID string, n int) ([]Player, error) {
 		cq.Queues = []*Queue{q}
 		q.Players = q.Players[:0]
 	}
+	if len(popped) > 0 {
+		cq.eta.recordPop(time.Now().UTC())
+	}
+	m.bump(cq)
 
 	return popped, nil
 }
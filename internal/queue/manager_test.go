@@ -1,7 +1,9 @@
 package queue
 
 import (
+	"errors"
 	"math/rand"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -77,3 +79,190 @@ func TestRaceRandomOps(t *testing.T) {
 	qs, _ := m.Queues(ch)
 	invariant(t, qs)
 }
+
+func ids(qs []*Queue) []string {
+	var out []string
+	for _, q := range qs {
+		for _, p := range q.Players {
+			out = append(out, p.ID)
+		}
+	}
+	return out
+}
+
+func TestUndoRestoresWhenUnchanged(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		_, _ = m.JoinAny(ch, id, id, 5)
+	}
+	seq, err := m.ResetAt(ch, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := m.Undo(ch, seq)
+	if err != nil || res.Merged {
+		t.Fatalf("want exact restore, got %+v %v", res, err)
+	}
+	qs, _ := m.Queues(ch)
+	if got := strings.Join(ids(qs), ""); got != "ABCDEFG" {
+		t.Fatalf("want ABCDEFG, got %s", got)
+	}
+	if _, err := m.Undo(ch, 0); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("want ErrNothingToUndo, got %v", err)
+	}
+}
+
+func TestUndoMergesLaterChanges(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		_, _ = m.JoinAny(ch, id, id, 5)
+	}
+	seq, _ := m.ResetAt(ch, 1) // removes A..E, F G move up
+	_, _ = m.JoinAny(ch, "H", "H", 5)
+	_, _ = m.LeaveAny(ch, "F")
+	_, _ = m.JoinAny(ch, "C", "C", 5) // C re-joined on its own
+
+	res, err := m.Undo(ch, seq)
+	if err != nil || !res.Merged {
+		t.Fatalf("want merge, got %+v %v", res, err)
+	}
+	qs, _ := m.Queues(ch)
+	invariant(t, qs)
+	// F left after the reset: stays out. H joined after: goes behind.
+	if got := strings.Join(ids(qs), ""); got != "ABCDEGH" {
+		t.Fatalf("want ABCDEGH, got %s", got)
+	}
+}
+
+func TestUndoStaleSeq(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	_, _ = m.JoinAny(ch, "A", "A", 5)
+	_, _ = m.JoinAny(ch, "B", "B", 5)
+	first, _ := m.Kick(ch, "A", 0)
+	_, _ = m.Kick(ch, "B", 0)
+	if _, err := m.Undo(ch, first); !errors.Is(err, ErrUndoStale) {
+		t.Fatalf("want ErrUndoStale, got %v", err)
+	}
+}
+
+func TestQueueIDsAreNotReused(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for k := 0; k < 12; k++ {
+		_, _ = m.JoinAny(ch, string(rune('A'+k)), "u", 5)
+	}
+	qs, _ := m.Queues(ch)
+	second := qs[1].ID
+	if _, err := m.DeleteQueue(ch, second, 0); err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < 6; k++ {
+		_, _ = m.JoinAny(ch, string(rune('a'+k)), "u", 5)
+	}
+	qs, _ = m.Queues(ch)
+	seen := map[string]bool{}
+	for _, q := range qs {
+		if q.ID == second || seen[q.ID] {
+			t.Fatalf("queue id %s reused", q.ID)
+		}
+		seen[q.ID] = true
+	}
+}
+
+func TestStaleVersionRejected(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	_, _ = m.JoinAny(ch, "A", "A", 5)
+	qs, v, _ := m.Snapshot(ch)
+	_, _ = m.JoinAny(ch, "B", "B", 5) // someone joins meanwhile
+
+	if _, err := m.ResetQueue(ch, qs[0].ID, v); !errors.Is(err, ErrStale) {
+		t.Fatalf("want ErrStale, got %v", err)
+	}
+	if _, err := m.Kick(ch, "A", v); !errors.Is(err, ErrStale) {
+		t.Fatalf("want ErrStale on kick, got %v", err)
+	}
+	if _, err := m.ResetQueue(ch, qs[0].ID, m.Version(ch)); err != nil {
+		t.Fatalf("fresh version should pass: %v", err)
+	}
+}
+
+func TestWatchDeliversLatest(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	w, cancel := m.Subscribe()
+	defer cancel()
+
+	_, _ = m.JoinAny(ch, "A", "A", 5)
+	_, _ = m.JoinAny(ch, "B", "B", 5) // coalesced with the pending change
+
+	select {
+	case <-w.Ready():
+	default:
+		t.Fatalf("no change signalled")
+	}
+	got := w.Take()
+	if len(got) != 1 || got[0].ChannelID != ch || got[0].Version != m.Version(ch) || len(got[0].Queues[0].Players) != 2 {
+		t.Fatalf("want latest snapshot, got %+v", got)
+	}
+
+	v := m.Version(ch)
+	_, _ = m.Batch(ch, 5, func(tx *Tx) error {
+		_ = tx.Join("C", "C")
+		return tx.Join("D", "D")
+	})
+	if got := w.Take(); len(got) != 1 || got[0].Version != v+1 {
+		t.Fatalf("batch must notify once, got %+v", got)
+	}
+}
+
+func TestWatchKeepsEveryChannel(t *testing.T) {
+	m := NewManager()
+	w, cancel := m.Subscribe()
+
+	_, _ = m.JoinAny("a", "A", "A", 5)
+	_, _ = m.JoinAny("b", "B", "B", 5)
+	_, _ = m.JoinAny("a", "C", "C", 5)
+
+	got := w.Take()
+	if len(got) != 2 || got[0].ChannelID != "a" || got[1].ChannelID != "b" {
+		t.Fatalf("want one change per channel, got %+v", got)
+	}
+	if len(got[0].Queues[0].Players) != 2 {
+		t.Fatalf("channel a should be at its latest state: %+v", got[0])
+	}
+
+	cancel()
+	if _, ok := <-w.Ready(); ok {
+		// drain the pending signal; the next receive sees the close
+		if _, ok := <-w.Ready(); ok {
+			t.Fatal("Ready should be closed after cancel")
+		}
+	}
+}
+
+func TestSetCapacityRelayouts(t *testing.T) {
+	m := NewManager()
+	ch := "c"
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		_, _ = m.JoinAny(ch, id, id, 5)
+	}
+	if err := m.SetCapacity(ch, 3); err != nil {
+		t.Fatal(err)
+	}
+	qs, _ := m.Queues(ch)
+	invariant(t, qs)
+	if len(qs) != 3 || qs[0].Capacity != 3 || strings.Join(ids(qs), "") != "ABCDEFG" {
+		t.Fatalf("want 3/3/1 in order at capacity 3, got %d queues %v", len(qs), ids(qs))
+	}
+
+	if err := m.SetCapacity(ch, 10); err != nil {
+		t.Fatal(err)
+	}
+	if qs, _ = m.Queues(ch); len(qs) != 1 || len(qs[0].Players) != 7 {
+		t.Fatalf("want everyone in Q1 at capacity 10, got %d queues", len(qs))
+	}
+}
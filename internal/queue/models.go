@@ -12,7 +12,7 @@ type Player struct {
 // represents a queue itself
 
 type Queue struct {
-	ID        string    // identifyer of queue (exp: "CSPFXCG-1")
+	ID        string    // stable identifier, "<channelID>:<seq>" (seq never reused)
 	Name      string    // queue name (exp: #queue-1)
 	Players   []Player  // list of player in queue
 	CreatedAt time.Time // when the queue was created
@@ -2,6 +2,8 @@
 // Queue compaction and housekeeping utilities.
 package queue
 
+import "time"
+
 // rebalanceForward fills earlier queues by pulling head players from later queues.
 // Intended to be called under the Manager mutex.
 func rebalanceForward(qs []*Queue, fromIdx int) {
@@ -36,3 +38,23 @@ func pruneTrailingEmpty(qs []*Queue) []*Queue {
 	}
 	return qs[:last+1]
 }
+
+// relayout pours players, in order, into the channel queues: existing queues
+// are reused front to back, tail queues are created when needed, and
+// trailing empties are pruned. Caller must hold the Manager mutex.
+func relayout(channelID string, cq *channelQueues, players []Player, capacity int) {
+	for _, q := range cq.Queues {
+		q.Players = q.Players[:0]
+	}
+	qi := 0
+	for _, p := range players {
+		for qi < len(cq.Queues) && len(cq.Queues[qi].Players) >= cq.Queues[qi].Capacity {
+			qi++
+		}
+		if qi == len(cq.Queues) {
+			appendQueue(channelID, cq, capacity, time.Now().UTC())
+		}
+		cq.Queues[qi].Players = append(cq.Queues[qi].Players, p)
+	}
+	cq.Queues = pruneTrailingEmpty(cq.Queues)
+}
@@ -0,0 +1,96 @@
+// Package queue - watch.go
+// Change subscriptions: watchers get the latest snapshot of every channel
+// that changed since they last looked.
+package queue
+
+import "sync"
+
+// Change is delivered to watchers after a mutation of one channel.
+type Change struct {
+	ChannelID string
+	Version   uint64
+	Queues    []*Queue // deep copy, safe to keep
+}
+
+// Watcher coalesces changes per channel: a slow consumer skips
+// intermediate states but always sees each channel's latest one.
+type Watcher struct {
+	mu      sync.Mutex
+	pending map[string]Change // channelID -> latest change not taken yet
+	order   []string          // channels in pending, first changed first
+	ready   chan struct{}     // 1-slot signal, closed on cancel
+}
+
+// Subscribe watches changes of every channel. Wait on Ready, then Take
+// the pending changes; cancel stops the watcher and closes Ready.
+//
+//	w, cancel := m.Subscribe()
+//	for range w.Ready() {
+//		for _, c := range w.Take() { ... }
+//	}
+func (m *Manager) Subscribe() (*Watcher, func()) {
+	w := &Watcher{pending: map[string]Change{}, ready: make(chan struct{}, 1)}
+
+	m.mu.Lock()
+	m.watchers = append(m.watchers, w)
+	m.mu.Unlock()
+
+	cancel := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for k, x := range m.watchers {
+			if x == w {
+				m.watchers = append(m.watchers[:k], m.watchers[k+1:]...)
+				close(w.ready)
+				return
+			}
+		}
+	}
+	return w, cancel
+}
+
+// Ready receives when there are changes to Take.
+func (w *Watcher) Ready() <-chan struct{} { return w.ready }
+
+// Take returns the pending changes, one per channel, and clears them.
+func (w *Watcher) Take() []Change {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]Change, 0, len(w.order))
+	for _, ch := range w.order {
+		out = append(out, w.pending[ch])
+	}
+	clear(w.pending)
+	w.order = w.order[:0]
+	return out
+}
+
+func (w *Watcher) push(ev Change) {
+	w.mu.Lock()
+	if _, ok := w.pending[ev.ChannelID]; !ok {
+		w.order = append(w.order, ev.ChannelID)
+	}
+	w.pending[ev.ChannelID] = ev
+	w.mu.Unlock()
+	select {
+	case w.ready <- struct{}{}:
+	default: // a signal is already pending
+	}
+}
+
+// bump marks a committed mutation of cq: increments its version and
+// notifies watchers. Caller must hold the write lock.
+func (m *Manager) bump(cq *channelQueues) {
+	cq.version++
+	if len(m.watchers) == 0 {
+		return
+	}
+	qs := make([]*Queue, 0, len(cq.Queues))
+	for _, q := range cq.Queues {
+		qs = append(qs, snapshot(q))
+	}
+	ev := Change{ChannelID: cq.id, Version: cq.version, Queues: qs}
+	for _, w := range m.watchers {
+		w.push(ev)
+	}
+}
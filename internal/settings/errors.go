@@ -0,0 +1,15 @@
+// Package settings - errors.go
+package settings
+
+import "fmt"
+
+// serr mirrors queue.qerr: comparable constants usable with errors.Is.
+type serr string
+
+func (e serr) Error() string { return string(e) }
+
+var (
+	ErrCapacity   = serr(fmt.Sprintf("capacity must be between %d and %d", MinCapacity, MaxCapacity))
+	ErrLFGMissing = serr(fmt.Sprintf("lfg missing must be between 1 and %d", MaxCapacity))
+	ErrNegative   = serr("durations can't be negative")
+)
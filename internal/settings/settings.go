@@ -0,0 +1,112 @@
+// Package settings - settings.go
+// Per-guild configuration set from Discord (/setup), persisted to disk.
+// Anything left empty falls back to the configuration (env or config file).
+package settings
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// FileName is the store's file inside the data dir.
+const FileName = "settings.json"
+
+// Capacity bounds accepted by Validate.
+const (
+	MinCapacity = 1
+	MaxCapacity = 10
+)
+
+// Guild is one guild's settings. Zero values mean "not set".
+type Guild struct {
+	QueueChannelID    string   `json:"queue_channel_id,omitempty"`
+	AnnounceChannelID string   `json:"announce_channel_id,omitempty"`
+	AdminRoleIDs      []string `json:"admin_role_ids,omitempty"`
+	VoiceCategoryIDs  []string `json:"voice_category_ids,omitempty"`
+	Capacity          int      `json:"capacity,omitempty"`
+
+	LobbyChannelID      string `json:"lobby_channel_id,omitempty"`
+	VoiceGraceSeconds   int    `json:"voice_grace_seconds,omitempty"`
+	TeamVoice           *bool  `json:"team_voice,omitempty"` // nil = from config
+	TeamVoiceCategoryID string `json:"team_voice_category_id,omitempty"`
+
+	LFGMissing                 int `json:"lfg_missing,omitempty"`
+	LFGCooldownMinutes         int `json:"lfg_cooldown_minutes,omitempty"`
+	QueuedRoleReconcileMinutes int `json:"queued_role_reconcile_minutes,omitempty"`
+}
+
+// Validate checks the fields that have a range.
+func (g Guild) Validate() error {
+	if g.Capacity != 0 && (g.Capacity < MinCapacity || g.Capacity > MaxCapacity) {
+		return ErrCapacity
+	}
+	if g.LFGMissing < 0 || g.LFGMissing > MaxCapacity {
+		return ErrLFGMissing
+	}
+	if g.VoiceGraceSeconds < 0 || g.LFGCooldownMinutes < 0 || g.QueuedRoleReconcileMinutes < 0 {
+		return ErrNegative
+	}
+	return nil
+}
+
+func (g Guild) clone() Guild {
+	g.AdminRoleIDs = slices.Clone(g.AdminRoleIDs)
+	g.VoiceCategoryIDs = slices.Clone(g.VoiceCategoryIDs)
+	if g.TeamVoice != nil {
+		on := *g.TeamVoice
+		g.TeamVoice = &on
+	}
+	return g
+}
+
+// Store is a concurrency-safe guild -> Guild map mirrored to a JSON file.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	byGuild map[string]Guild
+}
+
+// Open loads settings from path. An empty path yields an in-memory store.
+func Open(path string) (*Store, error) {
+	st := &Store{path: path, byGuild: make(map[string]Guild)}
+	if err := storage.LoadJSON(path, &st.byGuild); err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// Get returns the guild's settings; ok is false if it never ran /setup.
+func (st *Store) Get(guildID string) (Guild, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	g, ok := st.byGuild[guildID]
+	return g.clone(), ok
+}
+
+// Guilds lists the guilds with saved settings, sorted.
+func (st *Store) Guilds() []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	out := make([]string, 0, len(st.byGuild))
+	for id := range st.byGuild {
+		out = append(out, id)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Update applies fn to a copy of the guild's settings and saves the result
+// if it validates. The stored value is unchanged on error.
+func (st *Store) Update(guildID string, fn func(*Guild)) (Guild, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	g := st.byGuild[guildID].clone()
+	fn(&g)
+	if err := g.Validate(); err != nil {
+		return st.byGuild[guildID].clone(), err
+	}
+	st.byGuild[guildID] = g
+	return g.clone(), storage.SaveJSON(st.path, st.byGuild)
+}
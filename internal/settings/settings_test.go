@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdatePersistsAndValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	st, _ := Open(path)
+	if _, ok := st.Get("g"); ok {
+		t.Fatal("fresh store should have no settings")
+	}
+
+	g, err := st.Update("g", func(g *Guild) {
+		g.QueueChannelID = "q"
+		g.AdminRoleIDs = []string{"r1", "r2"}
+		g.Capacity = 5
+	})
+	if err != nil || g.QueueChannelID != "q" {
+		t.Fatalf("update = %+v, %v", g, err)
+	}
+
+	if _, err := st.Update("g", func(g *Guild) { g.Capacity = 99; g.QueueChannelID = "x" }); !errors.Is(err, ErrCapacity) {
+		t.Fatalf("want ErrCapacity, got %v", err)
+	}
+	if g, _ := st.Get("g"); g.QueueChannelID != "q" || g.Capacity != 5 {
+		t.Fatalf("failed update leaked: %+v", g)
+	}
+
+	// callers get copies
+	g, _ = st.Get("g")
+	g.AdminRoleIDs[0] = "mutated"
+	if g, _ := st.Get("g"); g.AdminRoleIDs[0] != "r1" {
+		t.Fatalf("Get leaked the stored slice: %v", g.AdminRoleIDs)
+	}
+
+	st2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, ok := st2.Get("g")
+	if !ok || g2.Capacity != 5 || len(g2.AdminRoleIDs) != 2 || g2.AdminRoleIDs[0] != "r1" {
+		t.Fatalf("reloaded = %+v", g2)
+	}
+
+	_, _ = st2.Update("a", func(g *Guild) { g.QueueChannelID = "qa" })
+	if got := st2.Guilds(); len(got) != 2 || got[0] != "a" || got[1] != "g" {
+		t.Fatalf("Guilds = %v", got)
+	}
+}
+
+func TestGuildVoiceAndLFGSettings(t *testing.T) {
+	st, _ := Open("")
+	on := true
+	if _, err := st.Update("g", func(g *Guild) {
+		g.LobbyChannelID = "lobby"
+		g.TeamVoice = &on
+		g.LFGMissing = 2
+		g.LFGCooldownMinutes = 30
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.Update("g", func(g *Guild) { g.LFGMissing = MaxCapacity + 1 }); !errors.Is(err, ErrLFGMissing) {
+		t.Fatalf("want ErrLFGMissing, got %v", err)
+	}
+	if _, err := st.Update("g", func(g *Guild) { g.VoiceGraceSeconds = -1 }); !errors.Is(err, ErrNegative) {
+		t.Fatalf("want ErrNegative, got %v", err)
+	}
+
+	// callers get their own TeamVoice
+	g, _ := st.Get("g")
+	*g.TeamVoice = false
+	if g, _ := st.Get("g"); g.TeamVoice == nil || !*g.TeamVoice || g.LFGMissing != 2 {
+		t.Fatalf("stored settings changed: %+v", g)
+	}
+}
@@ -0,0 +1,336 @@
+// Package stats - stats.go
+// Queue throughput analytics: wait times, abandonment, peak hours and
+// matches per day, bucketed per guild and UTC day and persisted to disk.
+package stats
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/storage"
+)
+
+// Retention is how long daily buckets are kept.
+const Retention = 90 * 24 * time.Hour
+
+// saveDelay folds the events of a burst into one write of the file.
+const saveDelay = 5 * time.Second
+
+const dayLayout = "2006-01-02"
+
+// Day aggregates one UTC day of a guild's queue activity.
+type Day struct {
+	GuildID     string  `json:"guild_id,omitempty"` // "" = saved before stats were per guild, see Claim
+	Date        string  `json:"date"`               // YYYY-MM-DD, UTC
+	Joins       int     `json:"joins"`
+	Popped      int     `json:"popped"`  // players called for a match
+	Left        int     `json:"left"`    // voluntary leaves (abandonment)
+	Removed     int     `json:"removed"` // kicks, bans, already playing
+	Matches     int     `json:"matches"`
+	WaitSum     int64   `json:"wait_sum_s"` // seconds, over popped players
+	WaitN       int     `json:"wait_n"`
+	WaitMax     int64   `json:"wait_max_s"`
+	JoinsByHour [24]int `json:"joins_by_hour"` // UTC hour of day
+}
+
+// Summary aggregates the days of a period.
+type Summary struct {
+	From, To      time.Time
+	Days          int // days in the period
+	Joins         int
+	Popped        int
+	Left          int
+	Removed       int
+	Matches       int
+	AvgWait       time.Duration
+	MaxWait       time.Duration
+	Abandonment   float64 // Left / (Left + Popped), 0..1
+	MatchesPerDay float64
+	PeakHours     []int // busiest UTC hours by joins, busiest first (max 3)
+}
+
+type saved struct {
+	Days       []Day                `json:"days"`
+	Digests    map[string]time.Time `json:"digests,omitempty"`     // guildID -> last weekly digest
+	LastDigest time.Time            `json:"last_digest,omitempty"` // before digests were per guild
+}
+
+type dayKey struct{ guildID, date string }
+
+// Store is a concurrency-safe stats store mirrored to a JSON file. Events
+// are written saveDelay after the first unsaved one; call Flush on shutdown.
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	days    map[dayKey]*Day
+	digests map[string]time.Time
+	dirty   bool
+	timer   *time.Timer // pending deferred save
+	saveErr error       // last deferred save failure, returned by the next event
+}
+
+// Open loads stats from path. An empty path yields an in-memory store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, days: make(map[dayKey]*Day), digests: make(map[string]time.Time)}
+	var sv saved
+	if err := storage.LoadJSON(path, &sv); err != nil {
+		return s, err
+	}
+	for k := range sv.Days {
+		d := sv.Days[k]
+		s.days[dayKey{d.GuildID, d.Date}] = &d
+	}
+	for g, t := range sv.Digests {
+		s.digests[g] = t
+	}
+	if _, ok := s.digests[""]; !ok && !sv.LastDigest.IsZero() {
+		s.digests[""] = sv.LastDigest
+	}
+	return s, nil
+}
+
+// Claim assigns the days and digest saved without a guild to guildID (the
+// guild the bot served alone before stats were per guild). A day the
+// guild already has is merged into, not replaced.
+func (s *Store) Claim(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var legacy []dayKey
+	for k := range s.days {
+		if k.guildID == "" {
+			legacy = append(legacy, k)
+		}
+	}
+	t, hadDigest := s.digests[""]
+	if len(legacy) == 0 && !hadDigest {
+		return nil
+	}
+	for _, k := range legacy {
+		old := s.days[k]
+		delete(s.days, k)
+		to := dayKey{guildID, k.date}
+		if d, ok := s.days[to]; ok {
+			d.merge(old)
+			continue
+		}
+		old.GuildID = guildID
+		s.days[to] = old
+	}
+	if hadDigest {
+		delete(s.digests, "")
+		if t.After(s.digests[guildID]) {
+			s.digests[guildID] = t
+		}
+	}
+	return s.saveLocked(time.Now())
+}
+
+// merge adds o's counters to d.
+func (d *Day) merge(o *Day) {
+	d.Joins += o.Joins
+	d.Popped += o.Popped
+	d.Left += o.Left
+	d.Removed += o.Removed
+	d.Matches += o.Matches
+	d.WaitSum += o.WaitSum
+	d.WaitN += o.WaitN
+	d.WaitMax = max(d.WaitMax, o.WaitMax)
+	for h, n := range o.JoinsByHour {
+		d.JoinsByHour[h] += n
+	}
+}
+
+// dayLocked returns the guild's bucket of t, creating it. Caller must hold
+// the mutex.
+func (s *Store) dayLocked(guildID string, t time.Time) *Day {
+	key := dayKey{guildID, t.UTC().Format(dayLayout)}
+	d, ok := s.days[key]
+	if !ok {
+		d = &Day{GuildID: guildID, Date: key.date}
+		s.days[key] = d
+	}
+	return d
+}
+
+// Joined records a player joining one of the guild's queues.
+func (s *Store) Joined(guildID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.dayLocked(guildID, at)
+	d.Joins++
+	d.JoinsByHour[at.UTC().Hour()]++
+	return s.touchLocked()
+}
+
+// Left records a player removed without being popped; only voluntary leaves
+// count as abandonment.
+func (s *Store) Left(guildID string, voluntary bool, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.dayLocked(guildID, at)
+	if voluntary {
+		d.Left++
+	} else {
+		d.Removed++
+	}
+	return s.touchLocked()
+}
+
+// Popped records a match call: one match and the wait of each popped player
+// (time from joining to being called; zero or negative waits are unknown).
+func (s *Store) Popped(guildID string, waits []time.Duration, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.dayLocked(guildID, at)
+	d.Matches++
+	d.Popped += len(waits)
+	for _, w := range waits {
+		if w <= 0 {
+			continue
+		}
+		sec := int64(w / time.Second)
+		d.WaitSum += sec
+		d.WaitN++
+		d.WaitMax = max(d.WaitMax, sec)
+	}
+	return s.touchLocked()
+}
+
+// Summary aggregates the guild's activity over [from, to) in whole UTC
+// days: as many daily buckets as the period has days (rounded), ending
+// with the day of to. From is moved back to the start of the first one.
+func (s *Store) Summary(guildID string, from, to time.Time) Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sum := Summary{To: to}
+	sum.Days = max(int(to.Sub(from).Round(24*time.Hour)/(24*time.Hour)), 1)
+	end := to.UTC().Add(-time.Nanosecond)
+	lastDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	sum.From = lastDay.AddDate(0, 0, 1-sum.Days)
+	first, last := sum.From.Format(dayLayout), lastDay.Format(dayLayout)
+	var hours [24]int
+	var waitSum int64
+	var waitN int
+	for key, d := range s.days {
+		if key.guildID != guildID || key.date < first || key.date > last {
+			continue
+		}
+		sum.Joins += d.Joins
+		sum.Popped += d.Popped
+		sum.Left += d.Left
+		sum.Removed += d.Removed
+		sum.Matches += d.Matches
+		waitSum += d.WaitSum
+		waitN += d.WaitN
+		sum.MaxWait = max(sum.MaxWait, time.Duration(d.WaitMax)*time.Second)
+		for h, n := range d.JoinsByHour {
+			hours[h] += n
+		}
+	}
+
+	if waitN > 0 {
+		sum.AvgWait = time.Duration(waitSum/int64(waitN)) * time.Second
+	}
+	if n := sum.Left + sum.Popped; n > 0 {
+		sum.Abandonment = float64(sum.Left) / float64(n)
+	}
+	sum.MatchesPerDay = float64(sum.Matches) / float64(sum.Days)
+	sum.PeakHours = peakHours(hours, 3)
+	return sum
+}
+
+func peakHours(hours [24]int, n int) []int {
+	idx := make([]int, 0, 24)
+	for h, c := range hours {
+		if c > 0 {
+			idx = append(idx, h)
+		}
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return hours[idx[i]] > hours[idx[j]] })
+	if len(idx) > n {
+		idx = idx[:n]
+	}
+	return idx
+}
+
+// DigestDue reports whether the guild's weekly digest should be posted: it
+// is Monday (UTC) and none was posted since that Monday started.
+func (s *Store) DigestDue(guildID string, now time.Time) bool {
+	now = now.UTC()
+	if now.Weekday() != time.Monday {
+		return false
+	}
+	monday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.digests[guildID].Before(monday)
+}
+
+// MarkDigest remembers that the guild's weekly digest was posted at t.
+func (s *Store) MarkDigest(guildID string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.digests[guildID] = t.UTC()
+	return s.saveLocked(t)
+}
+
+// touchLocked marks the store changed and schedules its save. It returns
+// the failure of the previous deferred save, if any.
+func (s *Store) touchLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	s.dirty = true
+	if s.timer == nil {
+		s.timer = time.AfterFunc(saveDelay, func() {
+			if err := s.Flush(); err != nil {
+				s.mu.Lock()
+				s.saveErr = err
+				s.mu.Unlock()
+			}
+		})
+	}
+	err := s.saveErr
+	s.saveErr = nil
+	return err
+}
+
+// Flush writes unsaved events now.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !s.dirty {
+		return nil
+	}
+	return s.saveLocked(time.Now())
+}
+
+// saveLocked prunes buckets older than Retention and writes the file.
+func (s *Store) saveLocked(now time.Time) error {
+	cutoff := now.UTC().Add(-Retention).Format(dayLayout)
+	out := saved{Days: make([]Day, 0, len(s.days)), Digests: s.digests}
+	for key, d := range s.days {
+		if key.date < cutoff {
+			delete(s.days, key)
+			continue
+		}
+		out.Days = append(out.Days, *d)
+	}
+	sort.Slice(out.Days, func(i, j int) bool {
+		if out.Days[i].Date != out.Days[j].Date {
+			return out.Days[i].Date < out.Days[j].Date
+		}
+		return out.Days[i].GuildID < out.Days[j].GuildID
+	})
+	if err := storage.SaveJSON(s.path, out); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,137 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummaryAggregatesPeriod(t *testing.T) {
+	s, _ := Open("")
+	day := time.Date(2026, 3, 2, 18, 30, 0, 0, time.UTC)
+
+	for k := 0; k < 4; k++ {
+		_ = s.Joined("g", day)
+	}
+	_ = s.Joined("g", day.Add(-3*time.Hour))
+	_ = s.Left("g", true, day)
+	_ = s.Left("g", false, day)
+	_ = s.Popped("g", []time.Duration{10 * time.Minute, 20 * time.Minute, 0}, day)
+	_ = s.Popped("g", nil, day.Add(24*time.Hour))
+	_ = s.Joined("g", day.Add(-10*24*time.Hour)) // outside the week
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	sum := s.Summary("g", from, from.Add(7*24*time.Hour))
+
+	if sum.Joins != 5 || sum.Matches != 2 || sum.Popped != 3 || sum.Left != 1 || sum.Removed != 1 {
+		t.Fatalf("unexpected counts %+v", sum)
+	}
+	if sum.AvgWait != 15*time.Minute || sum.MaxWait != 20*time.Minute {
+		t.Fatalf("waits: avg=%s max=%s", sum.AvgWait, sum.MaxWait)
+	}
+	if sum.Abandonment != 0.25 {
+		t.Fatalf("abandonment = %v, want 0.25", sum.Abandonment)
+	}
+	if len(sum.PeakHours) != 2 || sum.PeakHours[0] != 18 || sum.PeakHours[1] != 15 {
+		t.Fatalf("peak hours = %v", sum.PeakHours)
+	}
+	if sum.Days != 7 {
+		t.Fatalf("days = %d", sum.Days)
+	}
+}
+
+func TestDigestDueOncePerWeek(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	s, _ := Open(path)
+	monday := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if s.DigestDue("g", monday.Add(-24*time.Hour)) {
+		t.Fatalf("sunday is not due")
+	}
+	if !s.DigestDue("g", monday) {
+		t.Fatalf("monday should be due")
+	}
+	_ = s.MarkDigest("g", monday)
+
+	s2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s2.DigestDue("g", monday.Add(time.Hour)) {
+		t.Fatalf("already posted this week (after reload)")
+	}
+	if !s2.DigestDue("g", monday.Add(7*24*time.Hour)) {
+		t.Fatalf("next monday should be due")
+	}
+}
+
+func TestPerGuildAndClaim(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	now := time.Now().UTC() // Claim prunes by the wall clock
+	monday := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+	monday = monday.AddDate(0, 0, -int((monday.Weekday()+6)%7))
+	legacy := fmt.Sprintf(`{"days":[{"date":%q,"joins":4}],"last_digest":%q}`,
+		monday.Format("2006-01-02"), monday.Format(time.RFC3339))
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Joined("g1", monday) // same day as the legacy bucket: merged, not replaced
+	if err := s.Claim("g1"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Joined("g2", monday)
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s2, _ := Open(path)
+	start := monday.Add(-9 * time.Hour) // 00:00 UTC
+	week := func(g string) int { return s2.Summary(g, start, start.Add(7*24*time.Hour)).Joins }
+	if week("g1") != 5 || week("g2") != 1 {
+		t.Fatalf("joins per guild: g1=%d g2=%d", week("g1"), week("g2"))
+	}
+	if s2.DigestDue("g1", monday.Add(time.Hour)) {
+		t.Fatal("g1 inherited the legacy digest of this week")
+	}
+	if !s2.DigestDue("g2", monday.Add(time.Hour)) {
+		t.Fatal("g2 has its own digest and it is due")
+	}
+}
+
+func TestSummaryWholeDays(t *testing.T) {
+	s, _ := Open("")
+	now := time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC) // Monday afternoon
+	_ = s.Popped("g", nil, now.Add(-7*24*time.Hour))    // last Monday: 8th day back, outside
+	_ = s.Popped("g", nil, now.Add(-6*24*time.Hour))
+	_ = s.Popped("g", nil, now)
+
+	sum := s.Summary("g", now.Add(-7*24*time.Hour), now)
+	if sum.Days != 7 || sum.Matches != 2 || sum.MatchesPerDay != 2.0/7 {
+		t.Fatalf("want 2 matches over 7 days, got %+v", sum)
+	}
+	if want := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC); !sum.From.Equal(want) {
+		t.Fatalf("from = %s, want %s", sum.From, want)
+	}
+}
+
+func TestSavesAreDeferred(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	s, _ := Open(path)
+	_ = s.Joined("g", time.Now())
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("event written right away (err=%v)", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	s2, _ := Open(path)
+	if got := s2.Summary("g", time.Now().Add(-24*time.Hour), time.Now()).Joins; got != 1 {
+		t.Fatalf("joins after flush = %d", got)
+	}
+}
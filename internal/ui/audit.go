@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jose-valero/popflash-queue-bot/internal/audit"
+)
+
+// RenderAuditEntry is the embed posted to the audit channel for each action.
+func RenderAuditEntry(e audit.Entry) *discordgo.MessageEmbed {
+	emb := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🛡️ %s", e.Action),
+		Description: auditLine(e),
+		Color:       0x5865F2,
+		Timestamp:   e.At.Format("2006-01-02T15:04:05Z07:00"),
+		Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("audit #%d", e.ID)},
+	}
+	if e.Detail != "" {
+		emb.Fields = append(emb.Fields, &discordgo.MessageEmbedField{Name: "Detail", Value: e.Detail})
+	}
+	if e.Before != nil || e.After != nil {
+		emb.Fields = append(emb.Fields,
+			&discordgo.MessageEmbedField{Name: "Before", Value: snapshotText(e.Before), Inline: true},
+			&discordgo.MessageEmbedField{Name: "After", Value: snapshotText(e.After), Inline: true},
+		)
+	}
+	return emb
+}
+
+// RenderAuditLog lists entries compactly for /auditlog.
+func RenderAuditLog(entries []audit.Entry) *discordgo.MessageEmbed {
+	emb := &discordgo.MessageEmbed{Title: "🛡️ Audit log", Color: 0x5865F2}
+	if len(entries) == 0 {
+		emb.Description = "_No matching entries._"
+		return emb
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		line := fmt.Sprintf("`#%d` <t:%d:R> **%s** — %s", e.ID, e.At.Unix(), e.Action, auditLine(e))
+		if e.Before != nil || e.After != nil {
+			line += fmt.Sprintf(" · %s → %s", snapshotSizes(e.Before), snapshotSizes(e.After))
+		}
+		if b.Len()+len(line) > 3900 { // embed description limit is 4096
+			b.WriteString("…")
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+	emb.Description = b.String()
+	return emb
+}
+
+func auditLine(e audit.Entry) string {
+	s := "by " + mentionOr(e.ActorID, e.ActorName)
+	if e.TargetID != "" || e.TargetName != "" {
+		s += " → " + mentionOr(e.TargetID, e.TargetName)
+	}
+	return s
+}
+
+// mentionOr mentions real Discord ids and falls back to the name for mocks.
+func mentionOr(id, name string) string {
+	if id != "" && strings.Trim(id, "0123456789") == "" {
+		return "<@" + id + ">"
+	}
+	return safe(name)
+}
+
+func snapshotText(sn audit.Snapshot) string {
+	if len(sn) == 0 {
+		return "_(no queues)_"
+	}
+	var b strings.Builder
+	for _, q := range sn {
+		fmt.Fprintf(&b, "**%s** (%d/%d)", q.Name, len(q.Players), q.Capacity)
+		if len(q.Players) > 0 {
+			b.WriteString(": " + strings.Join(q.Players, ", "))
+		}
+		b.WriteString("\n")
+		if b.Len() > 900 { // field value limit is 1024
+			b.WriteString("…")
+			break
+		}
+	}
+	return b.String()
+}
+
+func snapshotSizes(sn audit.Snapshot) string {
+	if len(sn) == 0 {
+		return "∅"
+	}
+	parts := make([]string, 0, len(sn))
+	for _, q := range sn {
+		parts = append(parts, fmt.Sprintf("%d/%d", len(q.Players), q.Capacity))
+	}
+	return strings.Join(parts, " ")
+}
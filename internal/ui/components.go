@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/jose-valero/popflash-queue-bot/internal/queue"
@@ -17,14 +19,14 @@ func ComponentsForQueues(qs []*queue.Queue, isOpen bool) []discordgo.MessageComp
 		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
 				discordgo.Button{
-					Label:    "La Llevo", // if u need it changes for ur language, "la llevo" means "join"
+					Label:    text.Load().JoinLabel, // see text.go
 					Style:    discordgo.PrimaryButton,
 					CustomID: joinID,
 					Emoji:    &discordgo.ComponentEmoji{Name: "🌕"},
 					Disabled: !isOpen, // disabled if the queue is closed
 				},
 				discordgo.Button{
-					Label:    "Chau", // if u need it changes for ur language, "chau" means leave
+					Label:    text.Load().LeaveLabel,
 					Style:    discordgo.SecondaryButton,
 					CustomID: "queue_leave",
 					Emoji:    &discordgo.ComponentEmoji{Name: "👋"},
@@ -43,7 +45,9 @@ func ComponentsForQueues(qs []*queue.Queue, isOpen bool) []discordgo.MessageComp
 }
 
 // admin selectors, actions(reset/close) | kick
-func AdminComponentsForQueues(qs []*queue.Queue) []discordgo.MessageComponent {
+// version is the manager version of qs; it travels in the CustomIDs so the
+// router can reject clicks on a panel rendered before the queue changed.
+func AdminComponentsForQueues(qs []*queue.Queue, version uint64) []discordgo.MessageComponent {
 	comps := make([]discordgo.MessageComponent, 0, 2)
 
 	// actions by queue (reset/close), cap of 12 queues (24 options)
@@ -55,12 +59,12 @@ func AdminComponentsForQueues(qs []*queue.Queue) []discordgo.MessageComponent {
 			opts = append(opts,
 				discordgo.SelectMenuOption{
 					Label:       fmt.Sprintf("Reset Q#%d", k),
-					Value:       fmt.Sprintf("reset:%d", k),
+					Value:       "reset:" + qs[idx].ID,
 					Description: "Clear that queue",
 				},
 				discordgo.SelectMenuOption{
 					Label:       fmt.Sprintf("Close Q#%d", k),
-					Value:       fmt.Sprintf("close:%d", k),
+					Value:       "close:" + qs[idx].ID,
 					Description: "Delete that queue",
 				},
 			)
@@ -68,7 +72,7 @@ func AdminComponentsForQueues(qs []*queue.Queue) []discordgo.MessageComponent {
 		comps = append(comps, discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
 				discordgo.SelectMenu{
-					CustomID:    "queue_action",
+					CustomID:    fmt.Sprintf("queue_action:v%d", version),
 					Placeholder: "Actions… (reset/close)",
 					Options:     opts,
 				},
@@ -82,7 +86,7 @@ func AdminComponentsForQueues(qs []*queue.Queue) []discordgo.MessageComponent {
 		comps = append(comps, discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
 				discordgo.SelectMenu{
-					CustomID:    "queue_kick",
+					CustomID:    fmt.Sprintf("queue_kick:v%d", version),
 					Placeholder: "Kick a player…",
 					Options:     kopts,
 				},
@@ -108,3 +112,65 @@ func kickOptions(qs []*queue.Queue) []discordgo.SelectMenuOption {
 	}
 	return opts
 }
+
+// UndoComponents is the "Undo" button shown under destructive admin
+// confirmations; seq pins it to that specific action.
+func UndoComponents(seq uint64) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Undo",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("queue_undo:%d", seq),
+					Emoji:    &discordgo.ComponentEmoji{Name: "↩️"},
+				},
+			},
+		},
+	}
+}
+
+// PanelVersion extracts the version suffix of an admin CustomID
+// ("queue_kick:v42" -> 42). Legacy IDs without version return 0.
+func PanelVersion(customID string) uint64 {
+	k := strings.LastIndex(customID, ":v")
+	if k < 0 {
+		return 0
+	}
+	v, _ := strconv.ParseUint(customID[k+2:], 10, 64)
+	return v
+}
+
+// LFGComponents is the join button of an LFG cross-post; the CustomID
+// carries the queue channel because the post lives in another channel.
+func LFGComponents(queueChannelID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    text.Load().JoinLabel,
+					Style:    discordgo.PrimaryButton,
+					CustomID: "lfg_join:" + queueChannelID,
+					Emoji:    &discordgo.ComponentEmoji{Name: "🌕"},
+				},
+			},
+		},
+	}
+}
+
+// ReadyCheckComponents is the confirm button of a ready check; the CustomID
+// carries the check it answers.
+func ReadyCheckComponents(checkID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Ready",
+					Style:    discordgo.SuccessButton,
+					CustomID: "ready:" + checkID,
+					Emoji:    &discordgo.ComponentEmoji{Name: "✅"},
+				},
+			},
+		},
+	}
+}
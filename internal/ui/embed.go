@@ -20,13 +20,17 @@ type MatchCard struct {
 	Score2  *int
 }
 
-func buildQueuesDescription(qs []*queue.Queue) string {
+func buildQueuesDescription(qs []*queue.Queue, etas []time.Duration) string {
 	if len(qs) == 0 {
 		return "Use `/startqueue` para crear la primera."
 	}
 	var b strings.Builder
 	for idx, q := range qs {
-		fmt.Fprintf(&b, "**Fila #%d** (%d/%d)\n", idx+1, len(q.Players), q.Capacity) // if u need it changes for ur language, "fila" means "queue"
+		fmt.Fprintf(&b, "**Fila #%d** (%d/%d)", idx+1, len(q.Players), q.Capacity) // if u need it changes for ur language, "fila" means "queue"
+		if idx < len(etas) {
+			b.WriteString(" · ⏳ " + ETALabel(etas[idx]))
+		}
+		b.WriteString("\n")
 		if len(q.Players) == 0 {
 			b.WriteString("_(empty)_\n\n")
 			continue
@@ -66,11 +70,17 @@ func matchField(c MatchCard) *discordgo.MessageEmbedField {
 
 // ---------- principal embed (just one) ----------
 func RenderQueuesEmbed(qs []*queue.Queue, isOpen bool, cards []MatchCard) *discordgo.MessageEmbed {
+	return RenderQueuesEmbedWithETA(qs, isOpen, cards, nil)
+}
+
+// RenderQueuesEmbedWithETA is RenderQueuesEmbed with an estimated wait next
+// to each queue; etas[k] belongs to qs[k] (nil = no estimates).
+func RenderQueuesEmbedWithETA(qs []*queue.Queue, isOpen bool, cards []MatchCard, etas []time.Duration) *discordgo.MessageEmbed {
 	color := map[bool]int{true: 0x57F287, false: 0x808080}[isOpen]
 
 	emb := &discordgo.MessageEmbed{
 		Title:       queueTitle(isOpen),
-		Description: buildQueuesDescription(qs),
+		Description: buildQueuesDescription(qs, etas),
 		Color:       color,
 	}
 
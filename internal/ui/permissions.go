@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jose-valero/popflash-queue-bot/internal/perms"
+)
+
+// RenderPermissions is the embed for /permissions view: one line per action.
+func RenderPermissions(grants map[perms.Action]perms.Grant) *discordgo.MessageEmbed {
+	var b strings.Builder
+	for _, a := range perms.Actions {
+		b.WriteString("**" + string(a) + "** — ")
+		g, ok := grants[a]
+		if !ok {
+			b.WriteString("_admins (default)_\n")
+			continue
+		}
+		who := make([]string, 0, len(g.Roles)+len(g.Users))
+		for _, r := range g.Roles {
+			who = append(who, "<@&"+r+">")
+		}
+		for _, u := range g.Users {
+			who = append(who, "<@"+u+">")
+		}
+		b.WriteString(strings.Join(who, " ") + "\n")
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "🔐 Permissions",
+		Description: b.String(),
+		Color:       0x5865F2,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Administrators can always run every action."},
+	}
+}
@@ -0,0 +1,303 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jose-valero/popflash-queue-bot/internal/settings"
+)
+
+// Setup select CustomIDs ("setup:<field>").
+const (
+	SetupQueueChannel    = "setup:queue"
+	SetupAnnounceChannel = "setup:announce"
+	SetupAdminRoles      = "setup:admins"
+	SetupVoiceCategories = "setup:voicecats"
+	SetupCapacity        = "setup:capacity"
+
+	SetupLobbyChannel  = "setup:lobby"
+	SetupVoiceGrace    = "setup:grace"
+	SetupTeamVoice     = "setup:teamvoice"
+	SetupTeamCategory  = "setup:teamcat"
+	SetupLFGMissing    = "setup:lfgmissing"
+	SetupLFGCooldown   = "setup:lfgcooldown"
+	SetupRoleReconcile = "setup:reconcile"
+)
+
+// Setup sections: a message holds five selects, so the wizard is split in
+// pages picked with /setup section.
+const (
+	SetupSectionGeneral = "general"
+	SetupSectionVoice   = "voice"
+	SetupSectionLFG     = "lfg"
+)
+
+// SetupSectionOf is the section a wizard select belongs to.
+func SetupSectionOf(customID string) string {
+	switch customID {
+	case SetupLobbyChannel, SetupVoiceGrace, SetupTeamVoice, SetupTeamCategory:
+		return SetupSectionVoice
+	case SetupLFGMissing, SetupLFGCooldown, SetupRoleReconcile:
+		return SetupSectionLFG
+	default:
+		return SetupSectionGeneral
+	}
+}
+
+// SetupCurrent is what is in effect for the guild, shown next to settings
+// the guild hasn't set itself.
+type SetupCurrent struct {
+	Capacity            int
+	LobbyChannelID      string
+	VoiceGrace          time.Duration
+	TeamVoice           bool
+	TeamVoiceCategoryID string
+	LFGMissing          int
+	LFGCooldown         time.Duration
+	QueuedRoleReconcile time.Duration
+}
+
+// RenderSetup is the /setup embed for one section: what each setting is
+// right now.
+func RenderSetup(g settings.Guild, section string, cur SetupCurrent) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "🛠️ Setup",
+		Description: "Pick values below; each change is saved and applied right away. More with `/setup section:`.",
+		Color:       0x5865F2,
+	}
+	switch section {
+	case SetupSectionVoice:
+		embed.Title += " · Voice"
+		embed.Fields = setupVoiceFields(g, cur)
+	case SetupSectionLFG:
+		embed.Title += " · LFG and roles"
+		embed.Fields = setupLFGFields(g, cur)
+	default:
+		embed.Fields = setupGeneralFields(g, cur)
+	}
+	return embed
+}
+
+func setupGeneralFields(g settings.Guild, cur SetupCurrent) []*discordgo.MessageEmbedField {
+	chanOr := func(id string) string {
+		if id == "" {
+			return "_from config_"
+		}
+		return "<#" + id + ">"
+	}
+	list := func(ids []string, prefix string) string {
+		if len(ids) == 0 {
+			return "_from config_"
+		}
+		out := make([]string, len(ids))
+		for k, id := range ids {
+			out[k] = prefix + id + ">"
+		}
+		return strings.Join(out, " ")
+	}
+	players := fmt.Sprintf("%d _(from config)_", cur.Capacity)
+	if g.Capacity > 0 {
+		players = strconv.Itoa(g.Capacity)
+	}
+	return []*discordgo.MessageEmbedField{
+		{Name: "Queue channel", Value: chanOr(g.QueueChannelID), Inline: true},
+		{Name: "PopFlash announce channel", Value: chanOr(g.AnnounceChannelID), Inline: true},
+		{Name: "Players per queue", Value: players, Inline: true},
+		{Name: "Admin roles", Value: list(g.AdminRoleIDs, "<@&")},
+		{Name: "Voice categories", Value: list(g.VoiceCategoryIDs, "<#")},
+	}
+}
+
+func setupVoiceFields(g settings.Guild, cur SetupCurrent) []*discordgo.MessageEmbedField {
+	chanOr := func(own, current string) string {
+		switch {
+		case own != "":
+			return "<#" + own + ">"
+		case current != "":
+			return "<#" + current + "> _(from config)_"
+		default:
+			return "_off_"
+		}
+	}
+	grace := ShortDuration(cur.VoiceGrace)
+	if g.VoiceGraceSeconds == 0 {
+		grace += " _(from config)_"
+	}
+	team := onOff(cur.TeamVoice)
+	if g.TeamVoice == nil {
+		team += " _(from config)_"
+	}
+	return []*discordgo.MessageEmbedField{
+		{Name: "Queue lobby", Value: chanOr(g.LobbyChannelID, cur.LobbyChannelID), Inline: true},
+		{Name: "Out of voice grace", Value: grace, Inline: true},
+		{Name: "Team voice channels", Value: team, Inline: true},
+		{Name: "Team voice category", Value: chanOr(g.TeamVoiceCategoryID, cur.TeamVoiceCategoryID), Inline: true},
+	}
+}
+
+func setupLFGFields(g settings.Guild, cur SetupCurrent) []*discordgo.MessageEmbedField {
+	fromConfig := func(v string, set bool) string {
+		if !set {
+			v += " _(from config)_"
+		}
+		return v
+	}
+	return []*discordgo.MessageEmbedField{
+		{Name: "LFG ping at", Value: fromConfig(fmt.Sprintf("%d missing", cur.LFGMissing), g.LFGMissing > 0), Inline: true},
+		{Name: "LFG cooldown", Value: fromConfig(ShortDuration(cur.LFGCooldown), g.LFGCooldownMinutes > 0), Inline: true},
+		{Name: "Queued role check", Value: fromConfig("every "+ShortDuration(cur.QueuedRoleReconcile), g.QueuedRoleReconcileMinutes > 0), Inline: true},
+	}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// SetupComponents are the selects of one wizard section, prefilled with g.
+func SetupComponents(g settings.Guild, section string) []discordgo.MessageComponent {
+	switch section {
+	case SetupSectionVoice:
+		return setupVoiceComponents(g)
+	case SetupSectionLFG:
+		return setupLFGComponents(g)
+	default:
+		return setupGeneralComponents(g)
+	}
+}
+
+func setupDefaults(t discordgo.SelectMenuDefaultValueType, ids ...string) []discordgo.SelectMenuDefaultValue {
+	var out []discordgo.SelectMenuDefaultValue
+	for _, id := range ids {
+		if id != "" {
+			out = append(out, discordgo.SelectMenuDefaultValue{ID: id, Type: t})
+		}
+	}
+	return out
+}
+
+func setupRow(m discordgo.SelectMenu) discordgo.ActionsRow {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{m}}
+}
+
+// setupPresets is a string select of numbers with a "from config" entry
+// (value 0) first; label renders each number.
+func setupPresets(customID, placeholder string, current int, values []int, label func(int) string) discordgo.ActionsRow {
+	opts := []discordgo.SelectMenuOption{{Label: "From config", Value: "0", Default: current == 0}}
+	for _, v := range values {
+		opts = append(opts, discordgo.SelectMenuOption{Label: label(v), Value: strconv.Itoa(v), Default: v == current})
+	}
+	return setupRow(discordgo.SelectMenu{CustomID: customID, Placeholder: placeholder, Options: opts})
+}
+
+func setupGeneralComponents(g settings.Guild) []discordgo.MessageComponent {
+	zero := 0
+
+	caps := make([]discordgo.SelectMenuOption, 0, settings.MaxCapacity)
+	for n := settings.MinCapacity; n <= settings.MaxCapacity; n++ {
+		caps = append(caps, discordgo.SelectMenuOption{
+			Label:   fmt.Sprintf("%d players", n),
+			Value:   strconv.Itoa(n),
+			Default: n == g.Capacity,
+		})
+	}
+
+	return []discordgo.MessageComponent{
+		setupRow(discordgo.SelectMenu{
+			MenuType:      discordgo.ChannelSelectMenu,
+			CustomID:      SetupQueueChannel,
+			Placeholder:   "Queue channel",
+			ChannelTypes:  []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+			DefaultValues: setupDefaults(discordgo.SelectMenuDefaultValueChannel, g.QueueChannelID),
+		}),
+		setupRow(discordgo.SelectMenu{
+			MenuType:      discordgo.ChannelSelectMenu,
+			CustomID:      SetupAnnounceChannel,
+			Placeholder:   "PopFlash announce channel",
+			ChannelTypes:  []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+			DefaultValues: setupDefaults(discordgo.SelectMenuDefaultValueChannel, g.AnnounceChannelID),
+		}),
+		setupRow(discordgo.SelectMenu{
+			MenuType:      discordgo.RoleSelectMenu,
+			CustomID:      SetupAdminRoles,
+			Placeholder:   "Admin roles (none = config)",
+			MinValues:     &zero,
+			MaxValues:     10,
+			DefaultValues: setupDefaults(discordgo.SelectMenuDefaultValueRole, g.AdminRoleIDs...),
+		}),
+		setupRow(discordgo.SelectMenu{
+			MenuType:      discordgo.ChannelSelectMenu,
+			CustomID:      SetupVoiceCategories,
+			Placeholder:   "Voice categories (none = config)",
+			ChannelTypes:  []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory},
+			MinValues:     &zero,
+			MaxValues:     10,
+			DefaultValues: setupDefaults(discordgo.SelectMenuDefaultValueChannel, g.VoiceCategoryIDs...),
+		}),
+		setupRow(discordgo.SelectMenu{
+			CustomID:    SetupCapacity,
+			Placeholder: "Players per queue",
+			Options:     caps,
+		}),
+	}
+}
+
+func setupVoiceComponents(g settings.Guild) []discordgo.MessageComponent {
+	zero := 0
+	team := "config"
+	if g.TeamVoice != nil {
+		team = onOff(*g.TeamVoice)
+	}
+	return []discordgo.MessageComponent{
+		setupRow(discordgo.SelectMenu{
+			MenuType:      discordgo.ChannelSelectMenu,
+			CustomID:      SetupLobbyChannel,
+			Placeholder:   "Queue lobby: joining it joins the queue (none = config)",
+			ChannelTypes:  []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice},
+			MinValues:     &zero,
+			MaxValues:     1,
+			DefaultValues: setupDefaults(discordgo.SelectMenuDefaultValueChannel, g.LobbyChannelID),
+		}),
+		setupPresets(SetupVoiceGrace, "Out of voice grace", g.VoiceGraceSeconds,
+			[]int{30, 60, 120, 180, 300, 600},
+			func(secs int) string { return ShortDuration(time.Duration(secs)*time.Second) + " out of voice" }),
+		setupRow(discordgo.SelectMenu{
+			CustomID:    SetupTeamVoice,
+			Placeholder: "Team voice channels on pop",
+			Options: []discordgo.SelectMenuOption{
+				{Label: "From config", Value: "config", Default: team == "config"},
+				{Label: "On", Value: "on", Default: team == "on"},
+				{Label: "Off", Value: "off", Default: team == "off"},
+			},
+		}),
+		setupRow(discordgo.SelectMenu{
+			MenuType:      discordgo.ChannelSelectMenu,
+			CustomID:      SetupTeamCategory,
+			Placeholder:   "Team voice category (none = config)",
+			ChannelTypes:  []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory},
+			MinValues:     &zero,
+			MaxValues:     1,
+			DefaultValues: setupDefaults(discordgo.SelectMenuDefaultValueChannel, g.TeamVoiceCategoryID),
+		}),
+	}
+}
+
+func setupLFGComponents(g settings.Guild) []discordgo.MessageComponent {
+	mins := func(m int) string { return ShortDuration(time.Duration(m) * time.Minute) }
+	return []discordgo.MessageComponent{
+		setupPresets(SetupLFGMissing, "LFG ping when this many are missing", g.LFGMissing,
+			[]int{1, 2, 3, 4, 5},
+			func(n int) string { return fmt.Sprintf("Ping at %d missing", n) }),
+		setupPresets(SetupLFGCooldown, "LFG cooldown", g.LFGCooldownMinutes,
+			[]int{5, 10, 15, 30, 60},
+			func(m int) string { return mins(m) + " between pings" }),
+		setupPresets(SetupRoleReconcile, "Queued role check", g.QueuedRoleReconcileMinutes,
+			[]int{5, 10, 15, 30, 60},
+			func(m int) string { return "Check every " + mins(m) }),
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jose-valero/popflash-queue-bot/internal/stats"
+)
+
+// RenderStatsSummary is the embed for /queuestats and the weekly digest.
+func RenderStatsSummary(title string, s stats.Summary) *discordgo.MessageEmbed {
+	emb := &discordgo.MessageEmbed{
+		Title:       "📊 " + title,
+		Description: fmt.Sprintf("<t:%d:d> → <t:%d:d>", s.From.Unix(), s.To.Unix()),
+		Color:       0xFEE75C,
+	}
+	if s.Joins == 0 && s.Matches == 0 {
+		emb.Description += "\n_No queue activity in this period._"
+		return emb
+	}
+
+	wait := "—"
+	if s.AvgWait > 0 {
+		wait = fmt.Sprintf("avg %s · max %s", ShortDuration(s.AvgWait), ShortDuration(s.MaxWait))
+	}
+	peaks := "—"
+	if len(s.PeakHours) > 0 {
+		hs := make([]string, 0, len(s.PeakHours))
+		for _, h := range s.PeakHours {
+			hs = append(hs, fmt.Sprintf("%02d:00", h))
+		}
+		peaks = strings.Join(hs, ", ") + " UTC"
+	}
+
+	emb.Fields = []*discordgo.MessageEmbedField{
+		{Name: "Matches", Value: fmt.Sprintf("%d (%.1f/day)", s.Matches, s.MatchesPerDay), Inline: true},
+		{Name: "Joins", Value: fmt.Sprintf("%d", s.Joins), Inline: true},
+		{Name: "Called", Value: fmt.Sprintf("%d", s.Popped), Inline: true},
+		{Name: "Wait to be called", Value: wait, Inline: true},
+		{Name: "Abandonment", Value: fmt.Sprintf("%.0f%% (%d left)", s.Abandonment*100, s.Left), Inline: true},
+		{Name: "Peak hours", Value: peaks, Inline: true},
+	}
+	if s.Removed > 0 {
+		emb.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d removed by admins, bans or already playing (not counted as abandonment)", s.Removed)}
+	}
+	return emb
+}
@@ -0,0 +1,49 @@
+package ui
+
+import "sync/atomic"
+
+// Text is the configurable wording of the public queue message.
+type Text struct {
+	QueueTitle  string
+	OpenLabel   string
+	ClosedLabel string
+	JoinLabel   string
+	LeaveLabel  string
+}
+
+// if u need it changes for ur language: "la llevo" means "join", "chau" means leave
+var defaultText = Text{
+	QueueTitle:  "Ellos la llevan — Fila Global",
+	OpenLabel:   "🔓 Cola abierta",
+	ClosedLabel: "🔒 Cola cerrada",
+	JoinLabel:   "La Llevo",
+	LeaveLabel:  "Chau",
+}
+
+var text atomic.Pointer[Text]
+
+func init() {
+	t := defaultText
+	text.Store(&t)
+}
+
+// SetText replaces the wording; empty fields use the built-in default, so
+// a key removed from the config file goes back to it on reload.
+func SetText(t Text) {
+	cur := defaultText
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&cur.QueueTitle, t.QueueTitle},
+		{&cur.OpenLabel, t.OpenLabel},
+		{&cur.ClosedLabel, t.ClosedLabel},
+		{&cur.JoinLabel, t.JoinLabel},
+		{&cur.LeaveLabel, t.LeaveLabel},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	text.Store(&cur)
+}
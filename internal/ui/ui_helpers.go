@@ -18,11 +18,12 @@ func scoreLine(c MatchCard) string {
 }
 
 func queueTitle(isOpen bool) string {
-	state := "🔓 Cola abierta"
+	t := text.Load()
+	state := t.OpenLabel
 	if !isOpen {
-		state = "🔒 Cola cerrada"
+		state = t.ClosedLabel
 	}
-	return fmt.Sprintf("Ellos la llevan — Fila Global - %s", state)
+	return fmt.Sprintf("%s - %s", t.QueueTitle, state)
 }
 
 // humanize the time of match
@@ -78,3 +79,27 @@ func quoteBlock(s string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// ShortDuration renders d compactly for ephemeral replies ("1h 05m", "12m", "40s").
+func ShortDuration(d time.Duration) string {
+	if d <= 0 {
+		return "0s"
+	}
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// ETALabel renders an estimated wait rounded to minutes, so the public embed
+// doesn't change (and get re-edited) every few seconds.
+func ETALabel(d time.Duration) string {
+	if d < time.Minute {
+		return "en cualquier momento" // "any moment now"
+	}
+	return "~" + ShortDuration(d.Round(time.Minute))
+}
@@ -4,32 +4,140 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"github.com/jose-valero/popflash-queue-bot/internal/settings"
 )
 
 type Config struct {
-	Token             string
-	AppID             string
-	GuildID           string
-	Prefix            string
-	QueueChannelID    string // dónde renderizamos la UI / botones
-	AnnounceChannelID string // de dónde leemos los embeds de PopFlash
-	PopflashBase      string
-	PopflashToken     string
-	FFActiveMatchesUI bool
-	PollSeconds       int
+	Token             string `yaml:"token"`
+	AppID             string `yaml:"app_id"`
+	GuildID           string `yaml:"guild_id"`
+	Prefix            string `yaml:"prefix"`
+	QueueChannelID    string `yaml:"queue_channel_id"`    // dónde renderizamos la UI / botones
+	AnnounceChannelID string `yaml:"announce_channel_id"` // de dónde leemos los embeds de PopFlash
+	PopflashBase      string `yaml:"popflash_base"`
+	PopflashToken     string `yaml:"popflash_token"`
+	FFActiveMatchesUI bool   `yaml:"ff_active_matches_ui"`
+	PollSeconds       int    `yaml:"poll_seconds"`
+	DataDir           string `yaml:"data_dir"`         // dónde persistimos los stores JSON (links, etc.)
+	AuditChannelID    string `yaml:"audit_channel_id"` // opcional: dónde posteamos el audit log de acciones admin
+	StatsChannelID    string `yaml:"stats_channel_id"` // opcional: dónde posteamos el resumen semanal de stats
+
+	Capacity     int      `yaml:"capacity"`       // jugadores por fila
+	AdminRoleIDs []string `yaml:"admin_role_ids"` // además de Administrator
+
+	// LFG: pings a un rol cuando a la Fila #1 le faltan pocos jugadores
+	LFGRoleID    string        `yaml:"lfg_role_id"`    // rol a pingear ("" = desactivado)
+	LFGChannelID string        `yaml:"lfg_channel_id"` // opcional: canal donde cross-posteamos el LFG con botón de join
+	LFGMissing   int           `yaml:"lfg_missing"`    // pingear cuando faltan <= N jugadores
+	LFGCooldown  time.Duration `yaml:"lfg_cooldown"`   // mínimo entre pings
+
+	// Rol "en cola": se da al entrar a una fila y se quita al salir/pop
+	QueuedRoleID        string        `yaml:"queued_role_id"`        // "" = desactivado
+	QueuedRoleReconcile time.Duration `yaml:"queued_role_reconcile"` // cada cuánto corregimos el drift
+
+	Voice Voice  `yaml:"voice"`
+	UI    UIText `yaml:"ui"`
+
+	// Otros servidores además de GuildID (el principal); los que no estén
+	// acá se configuran con /setup
+	Guilds []Guild `yaml:"guilds"`
+
+	// File is the YAML file the config was read from ("" = env only).
+	File string `yaml:"-"`
+}
+
+// Guild is one extra guild served by the same process. Empty channels fall
+// back to /setup, and zero numbers (or a missing team_voice) to the
+// top-level values. Channels and roles belong to one guild, so the
+// top-level audit/stats/LFG/queued-role/lobby/team-category IDs only apply
+// to the primary guild; the others set their own here.
+type Guild struct {
+	ID                string `yaml:"id"`
+	QueueChannelID    string `yaml:"queue_channel_id"`
+	AnnounceChannelID string `yaml:"announce_channel_id"`
+	Capacity          int    `yaml:"capacity"`
+	AuditChannelID    string `yaml:"audit_channel_id"`
+	StatsChannelID    string `yaml:"stats_channel_id"`
+	LFGRoleID         string `yaml:"lfg_role_id"`
+	LFGChannelID      string `yaml:"lfg_channel_id"`
+	QueuedRoleID      string `yaml:"queued_role_id"`
+
+	LFGMissing          int           `yaml:"lfg_missing"`
+	LFGCooldown         time.Duration `yaml:"lfg_cooldown"`
+	QueuedRoleReconcile time.Duration `yaml:"queued_role_reconcile"`
+
+	LobbyChannelID      string        `yaml:"lobby_channel_id"`
+	VoiceGrace          time.Duration `yaml:"voice_grace"`
+	TeamVoice           *bool         `yaml:"team_voice"`
+	TeamVoiceCategoryID string        `yaml:"team_voice_category_id"`
+}
+
+// GuildConfigs is the primary guild (GuildID and the top-level channels and
+// roles) followed by Guilds.
+func (c *Config) GuildConfigs() []Guild {
+	out := make([]Guild, 0, len(c.Guilds)+1)
+	out = append(out, Guild{
+		ID: c.GuildID, QueueChannelID: c.QueueChannelID, AnnounceChannelID: c.AnnounceChannelID,
+		AuditChannelID: c.AuditChannelID, StatsChannelID: c.StatsChannelID,
+		LFGRoleID: c.LFGRoleID, LFGChannelID: c.LFGChannelID, QueuedRoleID: c.QueuedRoleID,
+		LobbyChannelID: c.Voice.LobbyChannelID, TeamVoiceCategoryID: c.Voice.TeamVoiceCategoryID,
+	})
+	return append(out, c.Guilds...)
+}
+
+// Voice is the voice policy (see internal/adapters/discord/voice.go).
+type Voice struct {
+	RequireToJoin       bool                     `yaml:"require_to_join"`
+	AllowedCategoryIDs  []string                 `yaml:"allowed_category_ids"`
+	AllowedCategoryName []string                 `yaml:"allowed_category_names"`
+	AllowedPrefixes     []string                 `yaml:"allowed_channel_prefixes"`
+	AFKChannelID        string                   `yaml:"afk_channel_id"`
+	Grace               time.Duration            `yaml:"grace"`           // fuera de voz antes de sacarte de la fila
+	GraceOverrides      map[string]time.Duration `yaml:"grace_overrides"` // por canal de fila
+	LobbyChannelID      string                   `yaml:"lobby_channel_id"`
+	LobbyLeaveDelay     time.Duration            `yaml:"lobby_leave_delay"`
+	TeamVoice           bool                     `yaml:"team_voice"`
+	TeamVoiceCategoryID string                   `yaml:"team_voice_category_id"`
 }
 
+// UIText holds the user-facing strings of the queue message. Empty fields
+// keep the built-in Spanish wording (internal/ui/text.go).
+type UIText struct {
+	QueueTitle  string `yaml:"queue_title"`
+	OpenLabel   string `yaml:"open_label"`
+	ClosedLabel string `yaml:"closed_label"`
+	JoinLabel   string `yaml:"join_label"`
+	LeaveLabel  string `yaml:"leave_label"`
+}
+
+// Load reads env (and .env), then the YAML file named by CONFIG_FILE if
+// set: values in the file win. The result is validated.
 func Load() (*Config, error) {
 	_ = godotenv.Load()
+	cfg := fromEnv()
+	if path := strings.TrimSpace(os.Getenv("CONFIG_FILE")); path != "" {
+		if err := cfg.mergeFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
 
+func fromEnv() *Config {
 	base := firstNonEmpty(os.Getenv("POPFLASH_BASE"), os.Getenv("POPFLASH_API_BASE"))
 	tok := firstNonEmpty(os.Getenv("POPFLASH_TOKEN"), os.Getenv("POPFLASH_API_TOKEN"))
 
-	cfg := &Config{
+	return &Config{
 		Token:             os.Getenv("DISCORD_BOT_TOKEN"),
 		AppID:             os.Getenv("DISCORD_APP_ID"),
 		GuildID:           os.Getenv("DISCORD_GUILD_ID"),
@@ -42,25 +150,161 @@ func Load() (*Config, error) {
 		// Feature Flag
 		FFActiveMatchesUI: strings.EqualFold(os.Getenv("FF_ACTIVE_MATCHES_UI"), "true"),
 		PollSeconds:       parseInt(os.Getenv("PF_POLL_SECONDS"), 60),
+		DataDir:           firstNonEmpty(strings.TrimSpace(os.Getenv("DATA_DIR")), "data"),
+		AuditChannelID:    strings.TrimSpace(os.Getenv("AUDIT_CHANNEL_ID")),
+		StatsChannelID:    strings.TrimSpace(os.Getenv("STATS_CHANNEL_ID")),
+
+		Capacity:     parseInt(os.Getenv("QUEUE_CAPACITY"), 5),
+		AdminRoleIDs: parseList(os.Getenv("ADMIN_ROLE_IDS")),
+
+		LFGRoleID:    strings.TrimSpace(os.Getenv("LFG_ROLE_ID")),
+		LFGChannelID: strings.TrimSpace(os.Getenv("LFG_CHANNEL_ID")),
+		LFGMissing:   parseInt(os.Getenv("LFG_MISSING"), 1),
+		LFGCooldown:  time.Duration(parseInt(os.Getenv("LFG_COOLDOWN_MINUTES"), 15)) * time.Minute,
+
+		QueuedRoleID:        strings.TrimSpace(os.Getenv("QUEUED_ROLE_ID")),
+		QueuedRoleReconcile: time.Duration(parseInt(os.Getenv("QUEUED_ROLE_RECONCILE_MINUTES"), 10)) * time.Minute,
+
+		Voice: Voice{
+			RequireToJoin:       parseBool(os.Getenv("VOICE_REQUIRE_TO_JOIN")),
+			AllowedCategoryIDs:  parseList(os.Getenv("VOICE_ALLOWED_CATEGORY_IDS")),
+			AllowedCategoryName: parseList(os.Getenv("VOICE_ALLOWED_CATEGORY_NAMES")),
+			AllowedPrefixes:     parseList(os.Getenv("VOICE_ALLOWED_CHANNEL_PREFIXES")),
+			AFKChannelID:        strings.TrimSpace(os.Getenv("AFK_CHANNEL_ID")),
+			Grace:               time.Duration(parseInt(os.Getenv("VOICE_GRACE_SECONDS"), 120)) * time.Second,
+			GraceOverrides:      parseGraceOverrides(os.Getenv("VOICE_GRACE_OVERRIDES")),
+			LobbyChannelID:      strings.Trim(strings.TrimSpace(os.Getenv("VOICE_LOBBY_CHANNEL_ID")), `"'`),
+			LobbyLeaveDelay:     time.Duration(parseInt(os.Getenv("VOICE_LOBBY_LEAVE_SECONDS"), 60)) * time.Second,
+			TeamVoice:           parseBool(os.Getenv("TEAM_VOICE_ENABLED")),
+			TeamVoiceCategoryID: strings.Trim(strings.TrimSpace(os.Getenv("TEAM_VOICE_CATEGORY_ID")), `"'`),
+		},
 	}
+}
 
-	if cfg.Token == "" {
-		return nil, errors.New("missing DISCORD_BOT_TOKEN")
+// Validate reports every problem at once.
+func (c *Config) Validate() error {
+	return errors.Join(c.validateRequired(), c.validateValues())
+}
+
+// validateRequired checks the credentials and IDs the bot can't start
+// without (usually from env, so a file alone may lack them).
+func (c *Config) validateRequired() error {
+	var errs []error
+	if c.Token == "" {
+		errs = append(errs, errors.New("missing DISCORD_BOT_TOKEN"))
 	}
-	if cfg.AppID == "" {
-		return nil, errors.New("missing DISCORD_APP_ID")
+	if c.AppID == "" {
+		errs = append(errs, errors.New("missing DISCORD_APP_ID"))
 	}
-	if cfg.GuildID == "" {
-		return nil, errors.New("missing DISCORD_GUILD_ID")
+	if c.GuildID == "" {
+		errs = append(errs, errors.New("missing DISCORD_GUILD_ID"))
 	}
-	if cfg.QueueChannelID == "" {
-		return nil, errors.New("missing DISCORD_QUEUE_CHANNEL_ID (or legacy DISCORD_CHANNEL_ID)")
+	if c.QueueChannelID == "" || c.AnnounceChannelID == "" {
+		set := c.primarySetup()
+		if c.QueueChannelID == "" && set.QueueChannelID == "" {
+			errs = append(errs, errors.New("missing DISCORD_CHANNEL_ID, and /setup didn't set a queue channel"))
+		}
+		if c.AnnounceChannelID == "" && set.AnnounceChannelID == "" {
+			errs = append(errs, errors.New("missing PF_ANNOUNCE_CHANNEL_ID, and /setup didn't set an announce channel"))
+		}
 	}
-	if cfg.AnnounceChannelID == "" {
-		return nil, errors.New("missing POPFLASH_ANNOUNCE_CHANNEL_ID (or PF_ANNOUNCE_CHANNEL_ID)")
+	return errors.Join(errs...)
+}
+
+// validateValues checks the shape and range of every value that is set.
+func (c *Config) validateValues() error {
+	var errs []error
+
+	ids := [][2]string{
+		{"guild_id", c.GuildID}, {"queue_channel_id", c.QueueChannelID}, {"announce_channel_id", c.AnnounceChannelID},
+		{"audit_channel_id", c.AuditChannelID}, {"stats_channel_id", c.StatsChannelID},
+		{"lfg_role_id", c.LFGRoleID}, {"lfg_channel_id", c.LFGChannelID}, {"queued_role_id", c.QueuedRoleID},
+		{"voice.afk_channel_id", c.Voice.AFKChannelID}, {"voice.lobby_channel_id", c.Voice.LobbyChannelID},
+		{"voice.team_voice_category_id", c.Voice.TeamVoiceCategoryID},
+	}
+	for _, id := range c.AdminRoleIDs {
+		ids = append(ids, [2]string{"admin_role_ids", id})
+	}
+	for _, id := range c.Voice.AllowedCategoryIDs {
+		ids = append(ids, [2]string{"voice.allowed_category_ids", id})
+	}
+	for ch := range c.Voice.GraceOverrides {
+		ids = append(ids, [2]string{"voice.grace_overrides", ch})
+	}
+	for k, g := range c.Guilds {
+		at := fmt.Sprintf("guilds[%d]", k)
+		ids = append(ids, [2]string{at + ".queue_channel_id", g.QueueChannelID},
+			[2]string{at + ".announce_channel_id", g.AnnounceChannelID},
+			[2]string{at + ".audit_channel_id", g.AuditChannelID}, [2]string{at + ".stats_channel_id", g.StatsChannelID},
+			[2]string{at + ".lfg_role_id", g.LFGRoleID}, [2]string{at + ".lfg_channel_id", g.LFGChannelID},
+			[2]string{at + ".queued_role_id", g.QueuedRoleID}, [2]string{at + ".lobby_channel_id", g.LobbyChannelID},
+			[2]string{at + ".team_voice_category_id", g.TeamVoiceCategoryID})
+	}
+	for _, kv := range ids {
+		if kv[1] != "" && !isSnowflake(kv[1]) {
+			errs = append(errs, fmt.Errorf("%s: %q is not a Discord ID", kv[0], kv[1]))
+		}
 	}
 
-	return cfg, nil
+	if c.Capacity < 1 || c.Capacity > 10 {
+		errs = append(errs, fmt.Errorf("capacity must be between 1 and 10 (got %d)", c.Capacity))
+	}
+	if c.PollSeconds < 0 {
+		errs = append(errs, fmt.Errorf("poll_seconds can't be negative"))
+	}
+	if c.LFGMissing < 1 {
+		errs = append(errs, fmt.Errorf("lfg_missing must be at least 1"))
+	}
+	if c.LFGCooldown < 0 || c.QueuedRoleReconcile <= 0 {
+		errs = append(errs, fmt.Errorf("lfg_cooldown / queued_role_reconcile must be positive"))
+	}
+	if c.Voice.Grace <= 0 || c.Voice.LobbyLeaveDelay < 0 {
+		errs = append(errs, fmt.Errorf("voice.grace must be positive and voice.lobby_leave_delay not negative"))
+	}
+	for ch, g := range c.Voice.GraceOverrides {
+		if g <= 0 {
+			errs = append(errs, fmt.Errorf("voice.grace_overrides[%s] must be positive", ch))
+		}
+	}
+	seen := map[string]bool{c.GuildID: true}
+	for k, g := range c.Guilds {
+		switch {
+		case !isSnowflake(g.ID):
+			errs = append(errs, fmt.Errorf("guilds[%d].id: %q is not a Discord ID", k, g.ID))
+		case seen[g.ID]:
+			errs = append(errs, fmt.Errorf("guilds[%d].id: %s listed twice", k, g.ID))
+		}
+		seen[g.ID] = true
+		if g.Capacity != 0 && (g.Capacity < 1 || g.Capacity > 10) {
+			errs = append(errs, fmt.Errorf("guilds[%d].capacity must be between 1 and 10 (got %d)", k, g.Capacity))
+		}
+		if g.LFGMissing < 0 {
+			errs = append(errs, fmt.Errorf("guilds[%d].lfg_missing can't be negative", k))
+		}
+		if g.LFGCooldown < 0 || g.QueuedRoleReconcile < 0 || g.VoiceGrace < 0 {
+			errs = append(errs, fmt.Errorf("guilds[%d]: lfg_cooldown / queued_role_reconcile / voice_grace can't be negative", k))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// primarySetup is the primary guild's /setup record in the data dir (zero
+// if there is none). Channels set there make the env ones optional.
+func (c *Config) primarySetup() settings.Guild {
+	if c.DataDir == "" || c.GuildID == "" {
+		return settings.Guild{}
+	}
+	st, err := settings.Open(filepath.Join(c.DataDir, settings.FileName))
+	if err != nil {
+		return settings.Guild{}
+	}
+	g, _ := st.Get(c.GuildID)
+	return g
+}
+
+func isSnowflake(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
 }
 
 func firstNonEmpty(v, d string) string {
@@ -70,14 +314,14 @@ func firstNonEmpty(v, d string) string {
 	return v
 }
 
-// func parseBool(v string) bool {
-// 	switch strings.ToLower(strings.TrimSpace(v)) {
-// 	case "1", "t", "true", "yes", "y", "on":
-// 		return true
-// 	default:
-// 		return false
-// 	}
-// }
+func parseBool(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "t", "true", "yes", "y", "on":
+		return true
+	default:
+		return false
+	}
+}
 
 func parseInt(v string, def int) int {
 	if v == "" {
@@ -89,13 +333,39 @@ func parseInt(v string, def int) int {
 	return def
 }
 
+// parseList splits a comma separated env value, dropping blanks and quotes.
+func parseList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.Trim(strings.TrimSpace(s), `"'`); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// parseGraceOverrides reads "channelID=seconds,channelID=seconds".
+func parseGraceOverrides(v string) map[string]time.Duration {
+	out := map[string]time.Duration{}
+	for _, kv := range parseList(v) {
+		id, secs, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(secs)); err == nil && n > 0 {
+			out[strings.TrimSpace(id)] = time.Duration(n) * time.Second
+		}
+	}
+	return out
+}
+
 func (c *Config) Redacted() string {
 	tok := "[set]"
 	if c.Token == "" {
 		tok = "[empty]"
 	}
 	return fmt.Sprintf(
-		"appID=%s guildID=%s prefix=%q queueChannelID=%s announceChannelID=%s token=%s",
-		c.AppID, c.GuildID, c.Prefix, c.QueueChannelID, c.AnnounceChannelID, tok,
+		"appID=%s guildID=%s (+%d) prefix=%q queueChannelID=%s announceChannelID=%s dataDir=%s token=%s",
+		c.AppID, c.GuildID, len(c.Guilds), c.Prefix, c.QueueChannelID, c.AnnounceChannelID, c.DataDir, tok,
 	)
 }
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const snow = "123456789012345678"
+
+func valid() *Config {
+	return &Config{
+		Token: "tok", AppID: snow, GuildID: snow,
+		QueueChannelID: snow, AnnounceChannelID: snow,
+		Capacity: 5, PollSeconds: 60, LFGMissing: 1,
+		LFGCooldown: 15 * time.Minute, QueuedRoleReconcile: 10 * time.Minute,
+		Voice: Voice{Grace: 2 * time.Minute, LobbyLeaveDelay: time.Minute},
+	}
+}
+
+func writeFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestChannelsFromSetup(t *testing.T) {
+	c := valid()
+	c.QueueChannelID, c.AnnounceChannelID = "", ""
+	c.DataDir = t.TempDir()
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "missing DISCORD_CHANNEL_ID") {
+		t.Fatalf("want missing channels without /setup, got %v", err)
+	}
+
+	rec := `{"` + snow + `":{"queue_channel_id":"` + snow + `","announce_channel_id":"` + snow + `"}}`
+	if err := os.WriteFile(filepath.Join(c.DataDir, "settings.json"), []byte(rec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("channels set by /setup: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		edit func(*Config)
+		want []string // substrings of the error; none = valid
+	}{
+		{"valid", func(*Config) {}, nil},
+		{"missing credentials", func(c *Config) { c.Token, c.AppID = "", "" },
+			[]string{"missing DISCORD_BOT_TOKEN", "missing DISCORD_APP_ID"}},
+		{"bad snowflake", func(c *Config) { c.AuditChannelID = "audit" },
+			[]string{`audit_channel_id: "audit" is not a Discord ID`}},
+		{"bad grace override key", func(c *Config) { c.Voice.GraceOverrides = map[string]time.Duration{"x": time.Second} },
+			[]string{"voice.grace_overrides"}},
+		{"capacity", func(c *Config) { c.Capacity = 11 }, []string{"capacity must be between 1 and 10"}},
+		{"durations", func(c *Config) { c.Voice.Grace = 0; c.LFGCooldown = -time.Second },
+			[]string{"voice.grace must be positive", "lfg_cooldown"}},
+		{"guilds", func(c *Config) {
+			c.Guilds = []Guild{{ID: snow}, {ID: "nope", Capacity: 20, LFGRoleID: "lfg"}}
+		}, []string{"guilds[0].id: " + snow + " listed twice", `guilds[1].id: "nope"`, "guilds[1].capacity",
+			`guilds[1].lfg_role_id: "lfg"`}},
+		{"guild voice and lfg", func(c *Config) {
+			c.Guilds = []Guild{{ID: "2", LobbyChannelID: "lobby", LFGMissing: -1, VoiceGrace: -time.Second}}
+		}, []string{`guilds[0].lobby_channel_id: "lobby"`, "guilds[0].lfg_missing", "guilds[0]: lfg_cooldown"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid()
+			tc.edit(c)
+			err := c.Validate()
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("want valid, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("want errors %q, got nil", tc.want)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q lacks %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+		check   func(*testing.T, *Config)
+	}{
+		{name: "empty file keeps everything", body: "", check: func(t *testing.T, c *Config) {
+			if c.Capacity != 5 || c.Voice.Grace != 2*time.Minute {
+				t.Fatalf("values changed: %+v", c)
+			}
+		}},
+		{name: "durations and nested keys", body: `
+capacity: 7
+lfg_cooldown: 90s
+voice:
+  grace: 1m30s
+  grace_overrides:
+    "` + snow + `": 45s
+ui:
+  join_label: Join
+`, check: func(t *testing.T, c *Config) {
+			if c.Capacity != 7 || c.LFGCooldown != 90*time.Second || c.Voice.Grace != 90*time.Second {
+				t.Fatalf("file not applied: %+v", c)
+			}
+			if c.Voice.GraceOverrides[snow] != 45*time.Second || c.UI.JoinLabel != "Join" {
+				t.Fatalf("nested keys not applied: %+v", c)
+			}
+			if c.Voice.LobbyLeaveDelay != time.Minute || c.Token != "tok" {
+				t.Fatal("keys missing from the file must keep their value")
+			}
+		}},
+		{name: "unknown key", body: "capacty: 7\n", wantErr: "field capacty not found"},
+		{name: "bad duration", body: "voice:\n  grace: soon\n", wantErr: "config file"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid()
+			path := writeFile(t, tc.body)
+			err := c.mergeFile(path)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("want error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.File != path {
+				t.Fatalf("File = %q", c.File)
+			}
+			tc.check(t, c)
+		})
+	}
+}
+
+func TestReloadKeepsCredentials(t *testing.T) {
+	t.Setenv("DISCORD_BOT_TOKEN", "env-token")
+	t.Setenv("DISCORD_APP_ID", "1")
+	t.Setenv("DISCORD_GUILD_ID", "2")
+	t.Setenv("DISCORD_CHANNEL_ID", snow)
+	t.Setenv("PF_ANNOUNCE_CHANNEL_ID", snow)
+
+	c := valid()
+	c.File = writeFile(t, "token: file-token\ndata_dir: elsewhere\ncapacity: 8\n")
+	c.DataDir = "data"
+
+	next, err := c.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Token != "tok" || next.AppID != snow || next.GuildID != snow || next.DataDir != "data" {
+		t.Fatalf("credentials/identity must survive a reload: %+v", next)
+	}
+	if next.Capacity != 8 || next.File != c.File {
+		t.Fatalf("non-credential settings should reload: %+v", next)
+	}
+
+	if err := os.WriteFile(c.File, []byte("capacity: 0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Reload(); err == nil {
+		t.Fatal("an invalid file must be rejected")
+	}
+}
+
+func TestRedactedYAML(t *testing.T) {
+	c := valid()
+	c.PopflashToken = "pf-secret"
+	c.Token = "discord-secret"
+	out, err := c.RedactedYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"pf-secret", "discord-secret"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("secret %q leaked:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"token: '[redacted]'", "popflash_token: '[redacted]'", "grace: 2m0s", "capacity: 5"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if c.Token != "discord-secret" {
+		t.Fatal("RedactedYAML must not modify the config")
+	}
+
+	c.PopflashToken = ""
+	if out, _ := c.RedactedYAML(); !strings.Contains(out, `popflash_token: ""`) {
+		t.Fatalf("an empty token should stay visibly empty:\n%s", out)
+	}
+}
+
+func TestCheckFileNeedsNoCredentials(t *testing.T) {
+	t.Setenv("DISCORD_BOT_TOKEN", "")
+	if _, err := CheckFile(writeFile(t, "capacity: 6\n")); err != nil {
+		t.Fatalf("file-only check without a token: %v", err)
+	}
+	if _, err := CheckFile(writeFile(t, "capacity: 60\n")); err == nil || !strings.Contains(err.Error(), "capacity") {
+		t.Fatalf("want capacity error, got %v", err)
+	}
+}
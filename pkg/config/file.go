@@ -0,0 +1,73 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeFile lays the YAML file at path over c. Keys left out keep their
+// env/default value; unknown keys are an error (typos shouldn't pass).
+func (c *Config) mergeFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) { // empty file = nothing to merge
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	c.File = path
+	return nil
+}
+
+// CheckFile validates the YAML file at path without requiring the
+// credentials and IDs that normally come from env: the file is laid over
+// env/defaults as Load does and every value is checked.
+func CheckFile(path string) (*Config, error) {
+	c := fromEnv()
+	if err := c.mergeFile(path); err != nil {
+		return nil, err
+	}
+	if err := c.validateValues(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload re-reads env and the config file and returns the validated result
+// with the credentials and identity of c kept: token, app/guild ID,
+// PopFlash base/token and data dir only change on restart.
+func (c *Config) Reload() (*Config, error) {
+	next := fromEnv()
+	if c.File != "" {
+		if err := next.mergeFile(c.File); err != nil {
+			return nil, err
+		}
+	}
+	next.Token, next.AppID, next.GuildID = c.Token, c.AppID, c.GuildID
+	next.PopflashBase, next.PopflashToken = c.PopflashBase, c.PopflashToken
+	next.DataDir = c.DataDir
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// RedactedYAML is the effective configuration as YAML with secrets hidden,
+// for `config check`.
+func (c *Config) RedactedYAML() (string, error) {
+	cp := *c
+	for _, s := range []*string{&cp.Token, &cp.PopflashToken} {
+		if *s != "" {
+			*s = "[redacted]"
+		}
+	}
+	out, err := yaml.Marshal(&cp)
+	return string(out), err
+}